@@ -0,0 +1,503 @@
+package gonnotation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Core annotations understood by every generator built on top of gonnotation
+
+// CoreAnnotationSpecs holds the specs of the core annotations, the parser consults it (besides the
+// specs in ParseOptions) to know which annotations allow Multiple occurrences
+var CoreAnnotationSpecs = AnnotationSpecs{
+	Annotations: []AnnotationSpec{
+		DeprecatedAnnotationSpec,
+		ExampleAnnotationSpec,
+		InterfacePolicyAnnotationSpec,
+		UnionAnnotationSpec,
+		EnumValueAnnotationSpec,
+		EnumIgnoreAnnotationSpec,
+		IncludeAnnotationSpec,
+		ExcludeAnnotationSpec,
+		AuthAnnotationSpec,
+		RouteGroupAnnotationSpec,
+		ScalarAnnotationSpec,
+		SerializeAnnotationSpec,
+		JSONShapeAnnotationSpec,
+	},
+}
+
+// DeprecationInfo holds the metadata of a @deprecated annotation
+type DeprecationInfo struct {
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"` // Why the element is deprecated, and what to use instead
+	Since  string `yaml:"since,omitempty" json:"since,omitempty"`   // Version since the element is deprecated
+}
+
+// DeprecatedAnnotationSpec is the spec of the @deprecated(reason, since) annotation
+var DeprecatedAnnotationSpec = AnnotationSpec{
+	Name: "deprecated",
+	Params: []AnnotationParam{
+		{
+			Name:        "reason",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			Description: "Why the element is deprecated, and what to use instead",
+		},
+		{
+			Name:        "since",
+			Types:       []string{"string"},
+			Description: "Version since the element is deprecated",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+		FieldAnnotationPlacement,
+		FunctionAnnotationPlacement,
+		EnumAnnotationPlacement,
+		EnumValueAnnotationPlacement,
+		InterfaceAnnotationPlacement,
+	},
+	Description: "Marks the element as deprecated",
+}
+
+// GetDeprecation looks for a @deprecated annotation in the list.
+// Returns the deprecation info and true if the element is deprecated, nil and false otherwise.
+func GetDeprecation(annotations []Annotation) (*DeprecationInfo, bool) {
+	for _, ann := range annotations {
+		if !DeprecatedAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		reason, _ := DeprecatedAnnotationSpec.GetParamValue("reason", ann)
+		since, _ := DeprecatedAnnotationSpec.GetParamValue("since", ann)
+		return &DeprecationInfo{
+			Reason: reason,
+			Since:  since,
+		}, true
+	}
+	return nil, false
+}
+
+// ExampleAnnotationSpec is the spec of the @example(value) annotation, the value can be a string, number, bool or JSON document
+var ExampleAnnotationSpec = AnnotationSpec{
+	Name: "example",
+	Params: []AnnotationParam{
+		{
+			Name:        "",
+			IsDefault:   true,
+			Types:       []string{"string", "int", "float", "bool", "json"},
+			Description: "Example value",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+		FieldAnnotationPlacement,
+	},
+	Description: "Adds an example value to the element",
+	Multiple:    true,
+}
+
+// GetExamples returns the typed values of all the @example annotations in the list, in declaration order
+func GetExamples(annotations []Annotation) []any {
+	var examples []any
+	for _, ann := range annotations {
+		if !ExampleAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		examples = append(examples, ParseExampleValue(examplePayload(ann)))
+	}
+	return examples
+}
+
+// ParseExampleValue converts a raw example into a typed Go value: int, float64, bool,
+// a decoded JSON document (map[string]any, []any) or a string
+func ParseExampleValue(raw string) any {
+	raw = strings.TrimSpace(raw)
+	if iv, err := strconv.Atoi(raw); err == nil {
+		return iv
+	}
+	// ParseFloat accepts NaN and Inf(inity) which aren't valid examples in any output format, keep them as strings
+	if fv, err := strconv.ParseFloat(raw, 64); err == nil && !math.IsNaN(fv) && !math.IsInf(fv, 0) {
+		return fv
+	}
+	switch raw {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	var jv any
+	if err := json.Unmarshal([]byte(raw), &jv); err == nil {
+		return jv
+	}
+	if len(raw) >= 2 && strings.HasPrefix(raw, "'") && strings.HasSuffix(raw, "'") {
+		return raw[1 : len(raw)-1]
+	}
+	return raw
+}
+
+// examplePayload extracts the raw value of an @example annotation from its original text,
+// JSON documents can contain commas and colons so the parsed params can't be used.
+// The text is matched against the name as written since Name can be normalized or aliased.
+func examplePayload(ann Annotation) string {
+	name := ann.WrittenName()
+	text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(ann.RawText), "@"))
+	if !strings.HasPrefix(text, name) {
+		return ann.Params[""]
+	}
+	text = strings.TrimSpace(text[len(name):])
+	if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		text = text[1 : len(text)-1]
+	}
+	return text
+}
+
+// InterfaceFieldPolicy defines how fields typed as interfaces or any are handled by generators
+type InterfaceFieldPolicy string
+
+const (
+	InterfaceFieldPolicyFreeForm InterfaceFieldPolicy = "freeform" // Emit a free-form object
+	InterfaceFieldPolicyUnion    InterfaceFieldPolicy = "union"    // Emit a union of the known implementers
+	InterfaceFieldPolicyError    InterfaceFieldPolicy = "error"    // Fail the generation
+)
+
+// IsValid reports whether the policy is one of the known policies
+func (p InterfaceFieldPolicy) IsValid() bool {
+	switch p {
+	case InterfaceFieldPolicyFreeForm, InterfaceFieldPolicyUnion, InterfaceFieldPolicyError:
+		return true
+	}
+	return false
+}
+
+// InterfacePolicyAnnotationSpec is the spec of the @interfacePolicy(policy) annotation,
+// it overrides the global interface field policy for a single field
+var InterfacePolicyAnnotationSpec = AnnotationSpec{
+	Name: "interfacePolicy",
+	Params: []AnnotationParam{
+		{
+			Name:        "policy",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			EnumValues:  []string{string(InterfaceFieldPolicyFreeForm), string(InterfaceFieldPolicyUnion), string(InterfaceFieldPolicyError)},
+			Description: "How the interface or any typed field is handled",
+			IsRequired:  true,
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		FieldAnnotationPlacement,
+	},
+	Description: "Overrides how an interface or any typed field is handled",
+}
+
+// GetInterfaceFieldPolicy returns the policy set by an @interfacePolicy annotation in the list,
+// or the given global policy if the annotation is absent. An unknown policy value is reported
+// as a *ParseError, the global policy is returned along with it.
+func GetInterfaceFieldPolicy(annotations []Annotation, global InterfaceFieldPolicy) (InterfaceFieldPolicy, error) {
+	for _, ann := range annotations {
+		if !InterfacePolicyAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		value, _ := InterfacePolicyAnnotationSpec.GetParamValue("policy", ann)
+		policy := InterfaceFieldPolicy(strings.ToLower(strings.TrimSpace(value)))
+		if !policy.IsValid() {
+			return global, &ParseError{
+				Annotation: ann.WrittenName(),
+				Msg:        fmt.Sprintf("unknown policy %q, valid policies are: %s", value, strings.Join(InterfacePolicyAnnotationSpec.GetParam("policy").EnumValues, ", ")),
+			}
+		}
+		return policy, nil
+	}
+	return global, nil
+}
+
+// UnionInfo describes a discriminated union (oneOf) declared with @union or @oneOf
+type UnionInfo struct {
+	Name          string   `yaml:"name" json:"name"`                                       // Name of the annotated interface or marker type
+	Types         []string `yaml:"types,omitempty" json:"types,omitempty"`                 // Member types, empty for @oneOf interfaces whose members are the implementers
+	Discriminator string   `yaml:"discriminator,omitempty" json:"discriminator,omitempty"` // Property that tells the members apart
+}
+
+// UnionAnnotationSpec is the spec of the @union(types="Cat,Dog", discriminator="kind") annotation
+var UnionAnnotationSpec = AnnotationSpec{
+	Name:    "union",
+	Aliases: []string{"oneOf"},
+	Params: []AnnotationParam{
+		{
+			Name:        "types",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string", "[]string"},
+			Description: "Member types of the union, defaults to the implementers when used on an interface",
+		},
+		{
+			Name:        "discriminator",
+			Types:       []string{"string"},
+			Description: "Property that tells the members apart",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+		InterfaceAnnotationPlacement,
+	},
+	Description: "Declares the annotated type as a union of other types",
+}
+
+// GetUnion looks for a @union or @oneOf annotation in the annotations of the named type.
+// Returns the union info and true if the type is a union, nil and false otherwise.
+func GetUnion(name string, annotations []Annotation) (*UnionInfo, bool) {
+	for _, ann := range annotations {
+		if !UnionAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		union := &UnionInfo{Name: name}
+		union.Types, _ = UnionAnnotationSpec.GetParamStringList("types", ann)
+		union.Discriminator, _ = UnionAnnotationSpec.GetParamValue("discriminator", ann)
+		return union, true
+	}
+	return nil, false
+}
+
+// EnumValueOptions holds the overrides declared on an enum constant with @enumValue and @enumIgnore
+type EnumValueOptions struct {
+	Name       string           `yaml:"name,omitempty" json:"name,omitempty"`             // Output name of the value, empty keeps the default
+	Ignore     bool             `yaml:"ignore,omitempty" json:"ignore,omitempty"`         // The value must be dropped from the generated enum
+	Deprecated *DeprecationInfo `yaml:"deprecated,omitempty" json:"deprecated,omitempty"` // Deprecation of the value, if any
+}
+
+// EnumValueAnnotationSpec is the spec of the @enumValue(name="ACTIVE", deprecated) annotation
+var EnumValueAnnotationSpec = AnnotationSpec{
+	Name: "enumValue",
+	Params: []AnnotationParam{
+		{
+			Name:        "name",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			Description: "Output name of the enum value",
+		},
+		{
+			Name:        "deprecated",
+			Types:       []string{"bool", "string"},
+			Description: "Marks the value as deprecated, a string value is used as the reason",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		EnumValueAnnotationPlacement,
+	},
+	Description: "Renames or deprecates an enum value",
+}
+
+// EnumIgnoreAnnotationSpec is the spec of the @enumIgnore annotation
+var EnumIgnoreAnnotationSpec = AnnotationSpec{
+	Name: "enumIgnore",
+	ValidOn: []AnnotationPlacement{
+		EnumValueAnnotationPlacement,
+	},
+	Description: "Drops the value from the generated enum",
+}
+
+// GetEnumValueOptions reads the @enumValue, @enumIgnore and @deprecated annotations of an enum constant
+func GetEnumValueOptions(annotations []Annotation) EnumValueOptions {
+	var opts EnumValueOptions
+	for _, ann := range annotations {
+		switch {
+		case EnumValueAnnotationSpec.Matches(ann.Name):
+			if name, ok := EnumValueAnnotationSpec.GetParamValue("name", ann); ok {
+				opts.Name = name
+			}
+			if deprecated, ok := ann.GetParamBool("deprecated"); ok {
+				if deprecated {
+					opts.Deprecated = &DeprecationInfo{}
+				}
+			} else if reason, ok := ann.GetParamValue("deprecated"); ok {
+				opts.Deprecated = &DeprecationInfo{Reason: reason}
+			}
+		case EnumIgnoreAnnotationSpec.Matches(ann.Name):
+			opts.Ignore = true
+		}
+	}
+	if opts.Deprecated == nil {
+		opts.Deprecated, _ = GetDeprecation(annotations)
+	}
+	return opts
+}
+
+// Inclusion is the decision taken by the @include / @exclude annotations of an element
+type Inclusion string
+
+const (
+	InclusionDefault Inclusion = ""        // No annotation, the generator strategy decides
+	InclusionInclude Inclusion = "include" // Always emitted by every plugin
+	InclusionExclude Inclusion = "exclude" // Never emitted by any plugin
+)
+
+// IncludeAnnotationSpec is the spec of the @include annotation
+var IncludeAnnotationSpec = AnnotationSpec{
+	Name: "include",
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+		EnumAnnotationPlacement,
+		InterfaceAnnotationPlacement,
+		FunctionAnnotationPlacement,
+	},
+	Description: "Includes the element in the output of every plugin regardless of the generation strategy",
+}
+
+// ExcludeAnnotationSpec is the spec of the @exclude (or @ignore) annotation
+var ExcludeAnnotationSpec = AnnotationSpec{
+	Name:    "exclude",
+	Aliases: []string{"ignore"},
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+		FieldAnnotationPlacement,
+		EnumAnnotationPlacement,
+		EnumValueAnnotationPlacement,
+		InterfaceAnnotationPlacement,
+		FunctionAnnotationPlacement,
+	},
+	Description: "Excludes the element from the output of every plugin",
+}
+
+// GetInclusion returns the inclusion decision of the @include / @exclude / @ignore annotations of an element,
+// exclusion takes precedence when both are present
+func GetInclusion(annotations []Annotation) Inclusion {
+	result := InclusionDefault
+	for _, ann := range annotations {
+		if ExcludeAnnotationSpec.Matches(ann.Name) {
+			return InclusionExclude
+		}
+		if IncludeAnnotationSpec.Matches(ann.Name) {
+			result = InclusionInclude
+		}
+	}
+	return result
+}
+
+// SecurityRequirement is a security scheme required by a function or route, declared with @auth or @security
+type SecurityRequirement struct {
+	Scheme string   `yaml:"scheme" json:"scheme"`                     // Security scheme name, e.g. "bearer", "apiKey", "oauth2"
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"` // Required scopes, e.g. "read:users"
+}
+
+// AuthAnnotationSpec is the spec of the @auth(scheme="bearer", scopes="read:users") annotation
+var AuthAnnotationSpec = AnnotationSpec{
+	Name:    "auth",
+	Aliases: []string{"security"},
+	Params: []AnnotationParam{
+		{
+			Name:        "scheme",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			Description: "Security scheme name, e.g. bearer, apiKey, oauth2",
+			IsRequired:  true,
+		},
+		{
+			Name:        "scopes",
+			Aliases:     []string{"scope"},
+			Types:       []string{"string", "[]string"},
+			Description: "Required scopes, e.g. read:users",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		FunctionAnnotationPlacement,
+		FunctionCallAnnotationPlacement,
+		InterfaceAnnotationPlacement,
+	},
+	Description: "Declares a security requirement of a function or route",
+	Multiple:    true,
+}
+
+// GetSecurityRequirements returns the security requirements declared by the @auth / @security annotations, in order.
+// A single bare flag is read as the scheme (@auth(bearer) is @auth(scheme="bearer")), an annotation without
+// a scheme (@auth) results in a requirement with an empty Scheme so generators can apply their default scheme.
+func GetSecurityRequirements(annotations []Annotation) []SecurityRequirement {
+	var requirements []SecurityRequirement
+	for _, ann := range annotations {
+		if !AuthAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		scheme, _ := AuthAnnotationSpec.GetParamValue("scheme", ann)
+		req := SecurityRequirement{Scheme: scheme}
+		if ann.HasParam("scopes", "scope") {
+			req.Scopes, _ = ann.GetParamStringList("scopes", "scope")
+		}
+		requirements = append(requirements, req)
+	}
+	return requirements
+}
+
+// RouteGroupInfo holds the values a @routeGroup declared on a function or receiver type passes on to its routes
+type RouteGroupInfo struct {
+	Prefix     string   `yaml:"prefix,omitempty" json:"prefix,omitempty"`         // Path prefix of the routes, e.g. "/api/v1"
+	Middleware []string `yaml:"middleware,omitempty" json:"middleware,omitempty"` // Middleware applied to every route, in order
+}
+
+// RouteGroupAnnotationSpec is the spec of the @routeGroup(prefix="/api/v1", middleware="auth,logging") annotation
+var RouteGroupAnnotationSpec = AnnotationSpec{
+	Name: "routeGroup",
+	Params: []AnnotationParam{
+		{
+			Name:        "prefix",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			Description: "Path prefix of the routes, e.g. /api/v1",
+		},
+		{
+			Name:        "middleware",
+			Aliases:     []string{"middlewares"},
+			Types:       []string{"string", "[]string"},
+			Description: "Middleware applied to every route of the group, in order",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+		FunctionAnnotationPlacement,
+	},
+	Description: "Declares the prefix and middleware inherited by the routes registered by a function or type",
+}
+
+// GetRouteGroup looks for a @routeGroup annotation in the list.
+// Returns the group info and true if found, nil and false otherwise.
+func GetRouteGroup(annotations []Annotation) (*RouteGroupInfo, bool) {
+	for _, ann := range annotations {
+		if !RouteGroupAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		group := &RouteGroupInfo{}
+		group.Prefix, _ = RouteGroupAnnotationSpec.GetParamValue("prefix", ann)
+		if ann.HasParam("middleware", "middlewares") {
+			group.Middleware, _ = ann.GetParamStringList("middleware", "middlewares")
+		}
+		return group, true
+	}
+	return nil, false
+}
+
+// Nest returns the group of the routes declared inside g with their own group, e.g. a function
+// of a receiver type: prefixes are joined and the middleware of g runs first
+func (g RouteGroupInfo) Nest(child RouteGroupInfo) RouteGroupInfo {
+	return RouteGroupInfo{
+		Prefix:     JoinRoutePath(g.Prefix, child.Prefix),
+		Middleware: append(slices.Clone(g.Middleware), child.Middleware...),
+	}
+}
+
+// JoinRoutePath joins route path segments with a single slash between them,
+// e.g. "/api/v1/" and "users" result in "/api/v1/users". Empty segments are skipped.
+func JoinRoutePath(segments ...string) string {
+	var parts []string
+	for _, s := range segments {
+		if s = strings.Trim(strings.TrimSpace(s), "/"); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
@@ -0,0 +1,76 @@
+package gonnotation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var benchSpecs = func() AnnotationSpecs {
+	var specs AnnotationSpecs
+	for i := range 50 {
+		specs.Annotations = append(specs.Annotations, AnnotationSpec{
+			Name:    fmt.Sprintf("annotation%d", i),
+			Aliases: []string{fmt.Sprintf("alias%d", i)},
+		})
+	}
+	return specs
+}()
+
+func BenchmarkParseAnnotationsFromText(b *testing.B) {
+	text := "User represents a user\n@schema(title:\"User\", description:\"A user\", readonly)\n@gqlType name=\"User\"\n@deprecated"
+	b.ReportAllocs()
+	for b.Loop() {
+		ParseAnnotationsFromText(text)
+	}
+}
+
+func BenchmarkNormalizeAnnotationName(b *testing.B) {
+	names := []string{"GqlType", "schema", "Deprecated", " field "}
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		i := 0
+		for b.Loop() {
+			NormalizeAnnotationName(names[i%len(names)])
+			i++
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		i := 0
+		for b.Loop() {
+			_ = strings.ToLower(strings.TrimSpace(names[i%len(names)]))
+			i++
+		}
+	})
+}
+
+func BenchmarkMatchesAnnotation(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		MatchesAnnotation("GqlType", "@gql", "type", "input", "enum")
+	}
+}
+
+func BenchmarkAnnotationMatcher(b *testing.B) {
+	m := NewAnnotationMatcher("@gql", "type", "input", "enum")
+	b.ReportAllocs()
+	for b.Loop() {
+		m.Matches("GqlType")
+	}
+}
+
+func BenchmarkGetAnnotationSpecByName(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		benchSpecs.GetAnnotationSpecByName("Alias49")
+	}
+}
+
+func BenchmarkAnnotationSpecIndex(b *testing.B) {
+	idx := benchSpecs.Index()
+	b.ReportAllocs()
+	for b.Loop() {
+		idx.Get("Alias49")
+	}
+}
@@ -0,0 +1,238 @@
+package gonnotation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Constraints is the normalized validation model of a field, shared across generators
+// so each one doesn't need to reparse the raw annotation params or struct tags
+type Constraints struct {
+	Required         bool     `yaml:"required,omitempty" json:"required,omitempty"`
+	Min              *float64 `yaml:"min,omitempty" json:"min,omitempty"`
+	Max              *float64 `yaml:"max,omitempty" json:"max,omitempty"`
+	ExclusiveMin     *float64 `yaml:"exclusiveMin,omitempty" json:"exclusiveMin,omitempty"`
+	ExclusiveMax     *float64 `yaml:"exclusiveMax,omitempty" json:"exclusiveMax,omitempty"`
+	MinLength        *int     `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	MaxLength        *int     `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+	Pattern          string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Format           string   `yaml:"format,omitempty" json:"format,omitempty"`
+	Enum             []string `yaml:"enum,omitempty" json:"enum,omitempty"`
+	MinItems         *int     `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	MaxItems         *int     `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+	UniqueItems      bool     `yaml:"uniqueItems,omitempty" json:"uniqueItems,omitempty"`
+	MultipleOf       *float64 `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
+	ContentMediaType string   `yaml:"contentMediaType,omitempty" json:"contentMediaType,omitempty"`
+}
+
+// ConstraintKind is the kind of a field as far as validator size rules are concerned:
+// min, max and len bound the value of numbers, the length of strings and the items of collections
+type ConstraintKind string
+
+const (
+	ConstraintKindUnknown    ConstraintKind = ""           // Size rules are ignored since their meaning is unknown
+	ConstraintKindString     ConstraintKind = "string"     // Size rules bound the length
+	ConstraintKindNumber     ConstraintKind = "number"     // Size rules bound the value
+	ConstraintKindCollection ConstraintKind = "collection" // Size rules bound the number of items (slices, arrays and maps)
+)
+
+// ConstraintAnnotationNames are the annotations whose params are read as constraints
+var ConstraintAnnotationNames = []string{"field", "validate"}
+
+// ValidatorAnnotationNames are the constraint annotations whose params follow the validator struct tag rules,
+// their min, max, len, gt and lt params are mapped following the kind of the field as in the struct tag
+var ValidatorAnnotationNames = []string{"validate"}
+
+// validatorSizeRules are the validator rules whose meaning depends on the kind of the field
+var validatorSizeRules = []string{"min", "gte", "max", "lte", "gt", "lt", "len"}
+
+// validatorFormats maps validator struct tag options to formats
+var validatorFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// IsEmpty reports whether no constraint is set
+func (c Constraints) IsEmpty() bool {
+	return !c.Required && c.Min == nil && c.Max == nil && c.ExclusiveMin == nil && c.ExclusiveMax == nil &&
+		c.MinLength == nil && c.MaxLength == nil && c.Pattern == "" && c.Format == "" && len(c.Enum) == 0 &&
+		c.MinItems == nil && c.MaxItems == nil && !c.UniqueItems && c.MultipleOf == nil && c.ContentMediaType == ""
+}
+
+// ParseConstraints builds the constraints of a field from the value of its go-playground/validator style
+// struct tag (e.g. reflect.StructTag(tag).Get("validate")) and its @field/@validate annotations.
+// The kind of the field tells how the validator size rules are mapped, e.g. min=1 is MinLength for
+// a string and MinItems for a slice. Annotations are applied last so they take precedence over the struct tag.
+func ParseConstraints(annotations []Annotation, validateTag string, kind ConstraintKind) Constraints {
+	var c Constraints
+	c.applyValidatorTag(validateTag, kind)
+
+	for _, ann := range annotations {
+		if !MatchesAnnotation(ann.Name, "", ConstraintAnnotationNames...) {
+			continue
+		}
+		c.applyAnnotation(ann, kind)
+	}
+	return c
+}
+
+// applyAnnotation applies the constraint params of an annotation, the size params of validator
+// style annotations (@validate) are mapped following the kind of the field
+func (c *Constraints) applyAnnotation(ann Annotation, kind ConstraintKind) {
+	if v, ok := ann.GetParamBool("required"); ok {
+		c.Required = v
+	}
+	if MatchesAnnotation(ann.Name, "", ValidatorAnnotationNames...) {
+		for _, rule := range validatorSizeRules {
+			if v, ok := ann.GetParamValue(rule); ok {
+				c.applyValidatorSize(rule, v, kind)
+			}
+		}
+		setFloat(&c.Min, ann, "minimum")
+		setFloat(&c.Max, ann, "maximum")
+		setFloat(&c.ExclusiveMin, ann, "exclusiveMin", "exclusiveMinimum")
+		setFloat(&c.ExclusiveMax, ann, "exclusiveMax", "exclusiveMaximum")
+	} else {
+		setFloat(&c.Min, ann, "min", "minimum")
+		setFloat(&c.Max, ann, "max", "maximum")
+		setFloat(&c.ExclusiveMin, ann, "exclusiveMin", "exclusiveMinimum", "gt")
+		setFloat(&c.ExclusiveMax, ann, "exclusiveMax", "exclusiveMaximum", "lt")
+	}
+	setFloat(&c.MultipleOf, ann, "multipleOf")
+	setInt(&c.MinLength, ann, "minLength")
+	setInt(&c.MaxLength, ann, "maxLength")
+	setInt(&c.MinItems, ann, "minItems")
+	setInt(&c.MaxItems, ann, "maxItems")
+	if v, ok := ann.GetParamValue("pattern"); ok {
+		c.Pattern = v
+	}
+	if v, ok := ann.GetParamValue("format"); ok {
+		c.Format = v
+	}
+	if v, ok := ann.GetParamValue("contentMediaType"); ok {
+		c.ContentMediaType = v
+	}
+	if ann.HasParam("enum", "oneOf") {
+		c.Enum, _ = ann.GetParamStringList("enum", "oneOf")
+	}
+	if v, ok := ann.GetParamBool("uniqueItems", "unique"); ok {
+		c.UniqueItems = v
+	}
+}
+
+// applyValidatorTag applies the rules of a go-playground/validator style struct tag value.
+// Rules after dive apply to the items of a collection, not to the field, so they are ignored,
+// as are the alternatives (a|b) since they can't be expressed as a single constraint.
+func (c *Constraints) applyValidatorTag(tag string, kind ConstraintKind) {
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "dive" {
+			return
+		}
+		if strings.Contains(rule, "|") {
+			continue
+		}
+		switch key {
+		case "required":
+			c.Required = true
+		case "min", "gte", "max", "lte", "gt", "lt", "len":
+			c.applyValidatorSize(key, value, kind)
+		case "oneof":
+			c.Enum = strings.Fields(value)
+		case "unique":
+			c.UniqueItems = true
+		default:
+			if format, ok := validatorFormats[key]; ok {
+				c.Format = format
+			}
+		}
+	}
+}
+
+// applyValidatorSize applies a min, max, len, gt or lt validator rule following the kind of the field
+func (c *Constraints) applyValidatorSize(rule string, value string, kind ConstraintKind) {
+	if kind == ConstraintKindNumber {
+		switch rule {
+		case "min", "gte":
+			c.Min = parseFloatPtr(value)
+		case "max", "lte":
+			c.Max = parseFloatPtr(value)
+		case "gt":
+			c.ExclusiveMin = parseFloatPtr(value)
+		case "lt":
+			c.ExclusiveMax = parseFloatPtr(value)
+		case "len":
+			c.Min = parseFloatPtr(value)
+			c.Max = parseFloatPtr(value)
+		}
+		return
+	}
+
+	var minSize, maxSize **int
+	switch kind {
+	case ConstraintKindString:
+		minSize, maxSize = &c.MinLength, &c.MaxLength
+	case ConstraintKindCollection:
+		minSize, maxSize = &c.MinItems, &c.MaxItems
+	default:
+		return
+	}
+	// Sizes are integers, so the exclusive bounds become inclusive ones
+	switch rule {
+	case "min", "gte":
+		*minSize = parseIntPtr(value)
+	case "max", "lte":
+		*maxSize = parseIntPtr(value)
+	case "gt":
+		*minSize = offsetIntPtr(parseIntPtr(value), 1)
+	case "lt":
+		*maxSize = offsetIntPtr(parseIntPtr(value), -1)
+	case "len":
+		*minSize = parseIntPtr(value)
+		*maxSize = parseIntPtr(value)
+	}
+}
+
+func setFloat(dst **float64, ann Annotation, name string, aliases ...string) {
+	if v, ok := ann.GetParamFloat(name, aliases...); ok {
+		*dst = &v
+	}
+}
+
+func setInt(dst **int, ann Annotation, name string, aliases ...string) {
+	if v, ok := ann.GetParamInt(name, aliases...); ok {
+		*dst = &v
+	}
+}
+
+func parseFloatPtr(s string) *float64 {
+	fv, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return nil
+	}
+	return &fv
+}
+
+func parseIntPtr(s string) *int {
+	iv, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	return &iv
+}
+
+func offsetIntPtr(v *int, offset int) *int {
+	if v == nil {
+		return nil
+	}
+	iv := *v + offset
+	return &iv
+}
@@ -0,0 +1,183 @@
+package gonnotation
+
+import (
+	"go/doc/comment"
+	"strings"
+	"unicode"
+)
+
+// Description is the documentation of an element extracted from its comments
+type Description struct {
+	Summary string `yaml:"summary,omitempty" json:"summary,omitempty"` // First sentence of the description
+	Text    string `yaml:"text,omitempty" json:"text,omitempty"`       // Full description, paragraphs and code blocks preserved
+}
+
+// ParseDescription extracts the description from a comment text (as returned by ast.CommentGroup.Text),
+// stripping the annotation lines and preserving paragraphs and indented code blocks.
+// When markdown is true godoc formatting (headings, lists, code blocks, links) is converted to Markdown.
+func ParseDescription(text string, markdown bool) Description {
+	return ParseDescriptionWithOptions(text, markdown, ParseOptions{})
+}
+
+// ParseDescriptionWithOptions extracts the description from a comment text as ParseDescription does,
+// also stripping the lines starting with the annotation marker of the options (e.g. "gonnotation: @schema")
+func ParseDescriptionWithOptions(text string, markdown bool, opts ParseOptions) Description {
+	lines := descriptionLines(text, opts.Marker)
+	if len(lines) == 0 {
+		return Description{}
+	}
+
+	desc := Description{
+		Summary: summary(lines),
+		Text:    strings.Join(lines, "\n"),
+	}
+	if markdown {
+		var p comment.Parser
+		var pr comment.Printer
+		desc.Text = strings.TrimSpace(string(pr.Markdown(p.Parse(desc.Text))))
+	}
+	return desc
+}
+
+// descriptionLines returns the lines of a comment text without the annotation lines (starting with @ or
+// with the marker, if any), collapsing the blank lines left behind by the stripped annotations
+func descriptionLines(text string, marker string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "@") || (marker != "" && strings.HasPrefix(trimmed, marker)) {
+			continue
+		}
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// summary returns the first sentence of the first paragraph of the description lines
+func summary(lines []string) string {
+	var paragraph []string
+	for _, line := range lines {
+		if line == "" {
+			break
+		}
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+	text := strings.Join(paragraph, " ")
+	for i := 0; i < len(text); i++ {
+		if text[i] != '.' && text[i] != '!' && text[i] != '?' {
+			continue
+		}
+		if i+1 < len(text) && text[i+1] != ' ' {
+			continue
+		}
+		if text[i] == '.' && !endsSentence(text[:i+1]) {
+			continue
+		}
+		return text[:i+1]
+	}
+	return text
+}
+
+// summaryAbbreviations are the abbreviations whose period doesn't end the summary sentence
+var summaryAbbreviations = []string{"e.g.", "i.e.", "cf.", "vs."}
+
+// endsSentence reports whether the period ending the text ends a sentence. As in go/doc synopses a period
+// after a single upper case letter is an initial (J. Smith) and doesn't, neither does a known abbreviation.
+func endsSentence(text string) bool {
+	word := text[strings.LastIndexByte(text, ' ')+1:]
+	if n := len(word); n >= 2 && unicode.IsUpper(rune(word[n-2])) && (n == 2 || !unicode.IsUpper(rune(word[n-3]))) {
+		return false
+	}
+	for _, abbr := range summaryAbbreviations {
+		if strings.EqualFold(word, abbr) {
+			return false
+		}
+	}
+	return true
+}
+
+// DescriptionRef represents a godoc link found in a description, e.g. [OtherType], [pkg.Func] or [Type.Method]
+type DescriptionRef struct {
+	Text    string `yaml:"text" json:"text"`                           // Link text as written, e.g. "pkg.Func"
+	Package string `yaml:"package,omitempty" json:"package,omitempty"` // Package name or import path, empty for the current package
+	Recv    string `yaml:"recv,omitempty" json:"recv,omitempty"`       // Receiver type for method links
+	Name    string `yaml:"name" json:"name"`                           // Referenced type, function or method name
+}
+
+// ParseDescriptionRefs extracts the godoc links from a comment text so documentation generators can
+// render cross-references between types instead of plain text. Every [Name] is considered a link since
+// the symbols of the package are not known at this point, consumers decide which ones resolve.
+// Annotation lines are stripped first, so annotation params like @field(enum:[a, b]) aren't read as links.
+func ParseDescriptionRefs(text string) []DescriptionRef {
+	return ParseDescriptionRefsWithOptions(text, ParseOptions{})
+}
+
+// ParseDescriptionRefsWithOptions extracts the godoc links from a comment text as ParseDescriptionRefs does,
+// also stripping the lines starting with the annotation marker of the options
+func ParseDescriptionRefsWithOptions(text string, opts ParseOptions) []DescriptionRef {
+	lines := descriptionLines(text, opts.Marker)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	p := comment.Parser{
+		LookupPackage: func(name string) (string, bool) {
+			return name, true
+		},
+		LookupSym: func(recv, name string) bool {
+			return true
+		},
+	}
+
+	var refs []DescriptionRef
+	for _, block := range p.Parse(strings.Join(lines, "\n")).Content {
+		refs = append(refs, collectBlockRefs(block)...)
+	}
+	return refs
+}
+
+// collectBlockRefs collects the doc links of a comment block
+func collectBlockRefs(block comment.Block) []DescriptionRef {
+	var refs []DescriptionRef
+	switch b := block.(type) {
+	case *comment.Paragraph:
+		refs = append(refs, collectTextRefs(b.Text)...)
+	case *comment.Heading:
+		refs = append(refs, collectTextRefs(b.Text)...)
+	case *comment.List:
+		for _, item := range b.Items {
+			for _, c := range item.Content {
+				refs = append(refs, collectBlockRefs(c)...)
+			}
+		}
+	}
+	return refs
+}
+
+// collectTextRefs collects the doc links of a list of inline texts
+func collectTextRefs(texts []comment.Text) []DescriptionRef {
+	var refs []DescriptionRef
+	for _, t := range texts {
+		if link, ok := t.(*comment.DocLink); ok {
+			ref := DescriptionRef{
+				Package: link.ImportPath,
+				Recv:    link.Recv,
+				Name:    link.Name,
+			}
+			for _, lt := range link.Text {
+				if plain, ok := lt.(comment.Plain); ok {
+					ref.Text += string(plain)
+				}
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,93 @@
+package gonnotation
+
+import (
+	"fmt"
+	"maps"
+)
+
+// DuplicatePolicy defines what happens when the same annotation appears more than once on one element
+type DuplicatePolicy string
+
+const (
+	DuplicateKeepAll   DuplicatePolicy = ""      // Keep every occurrence, consumers decide (default)
+	DuplicateError     DuplicatePolicy = "error" // Keep the first occurrence and report the conflicting ones
+	DuplicateFirstWins DuplicatePolicy = "first" // Keep the first occurrence
+	DuplicateLastWins  DuplicatePolicy = "last"  // Keep the last occurrence
+	DuplicateMerge     DuplicatePolicy = "merge" // Merge the params into the first occurrence, later values win
+)
+
+// IsValid reports whether the policy is one of the known policies
+func (p DuplicatePolicy) IsValid() bool {
+	switch p {
+	case DuplicateKeepAll, DuplicateError, DuplicateFirstWins, DuplicateLastWins, DuplicateMerge:
+		return true
+	}
+	return false
+}
+
+// resolveDuplicates applies the duplicate policy to the annotations of one element, lines holds the
+// line of each annotation for diagnostics. Annotations whose spec (in opts.Specs or CoreAnnotationSpecs)
+// allows Multiple are never duplicates. An unknown policy is reported and every annotation is kept.
+func resolveDuplicates(annotations []Annotation, lines []int, opts ParseOptions) ([]Annotation, ParseErrors) {
+	if !opts.DuplicatePolicy.IsValid() {
+		return annotations, ParseErrors{{
+			Msg: fmt.Sprintf("unknown duplicate policy %q, valid policies are: %q, %q, %q, %q, %q", opts.DuplicatePolicy,
+				DuplicateKeepAll, DuplicateError, DuplicateFirstWins, DuplicateLastWins, DuplicateMerge),
+		}}
+	}
+	if opts.DuplicatePolicy == DuplicateKeepAll || len(annotations) < 2 {
+		return annotations, nil
+	}
+
+	var errs ParseErrors
+	result := make([]Annotation, 0, len(annotations))
+	seen := make(map[string]int) // normalized name -> index in result
+
+	for i, ann := range annotations {
+		if allowsMultiple(ann.Name, opts.Specs) {
+			result = append(result, ann)
+			continue
+		}
+
+		key := opts.Specs.normalizeName(ann.Name)
+		idx, exists := seen[key]
+		if !exists {
+			seen[key] = len(result)
+			result = append(result, ann)
+			continue
+		}
+
+		switch opts.DuplicatePolicy {
+		case DuplicateError:
+			if !maps.Equal(result[idx].Params, ann.Params) {
+				errs = append(errs, &ParseError{
+					Line:       lines[i],
+					Annotation: ann.WrittenName(),
+					Msg:        fmt.Sprintf("conflicts with the previous @%s", result[idx].WrittenName()),
+				})
+			}
+		case DuplicateLastWins:
+			result[idx] = ann
+		case DuplicateMerge:
+			merged := result[idx]
+			merged.Params = maps.Clone(merged.Params)
+			maps.Copy(merged.Params, ann.Params)
+			merged.RawText = merged.RawText + "\n" + ann.RawText
+			result[idx] = merged
+		}
+	}
+
+	return result, errs
+}
+
+// allowsMultiple checks if the spec of an annotation, looked up in the given specs first and then
+// in the core specs, allows multiple occurrences per element
+func allowsMultiple(name string, specs AnnotationSpecs) bool {
+	if spec := specs.GetAnnotationSpecByName(name); spec != nil {
+		return spec.Multiple
+	}
+	if spec := CoreAnnotationSpecs.GetAnnotationSpecByName(name); spec != nil {
+		return spec.Multiple
+	}
+	return false
+}
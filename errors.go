@@ -0,0 +1,43 @@
+package gonnotation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError is a problem found while parsing the annotations of a comment text
+type ParseError struct {
+	Line       int    // Line of the annotation in the comment text, starting at 1
+	Annotation string // Name of the annotation as written, empty for problems not tied to an annotation
+	Msg        string
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Msg
+	if e.Annotation != "" {
+		msg = fmt.Sprintf("@%s: %s", e.Annotation, msg)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, msg)
+	}
+	return msg
+}
+
+// ParseErrors collects all the problems found while parsing instead of failing on the first one
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Err returns nil if there are no errors, the list otherwise
+func (e ParseErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
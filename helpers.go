@@ -1,6 +1,7 @@
 package gonnotation
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -110,25 +111,7 @@ func (a *Annotation) GetParamFloatOrDefault(name string, def float64, aliases ..
 // Returns (list,true) if any source is present, (nil,false) otherwise.
 func (a *Annotation) GetParamStringList(name string, aliases ...string) ([]string, bool) {
 	if raw, ok := a.GetParamValue(name, aliases...); ok && strings.TrimSpace(raw) != "" {
-		// Remove array brackets if present
-		raw = strings.TrimSpace(raw)
-
-		raw = strings.TrimPrefix(raw, "[")
-		raw = strings.TrimSuffix(raw, "]")
-
-		sepReplacer := strings.NewReplacer(";", ",")
-		clean := sepReplacer.Replace(raw)
-		parts := strings.Split(clean, ",")
-		var list []string
-		for _, p := range parts {
-			p = strings.TrimSpace(p)
-			// Remove quotes if present
-			p = strings.Trim(p, `"'`)
-			if p != "" {
-				list = append(list, p)
-			}
-		}
-		return list, true
+		return splitParamList(raw), true
 	}
 	// Fallback to argN style parameters
 	var list []string
@@ -147,6 +130,29 @@ func (a *Annotation) GetParamStringList(name string, aliases ...string) ([]strin
 }
 
 // GetParamStringListOrDefault returns the string list parameter or the provided default.
+// splitParamList splits a list param value like "a, b", "[a, b]" or "a;b" into its items
+func splitParamList(raw string) []string {
+	// Remove array brackets if present
+	raw = strings.TrimSpace(raw)
+
+	raw = strings.TrimPrefix(raw, "[")
+	raw = strings.TrimSuffix(raw, "]")
+
+	sepReplacer := strings.NewReplacer(";", ",")
+	clean := sepReplacer.Replace(raw)
+	parts := strings.Split(clean, ",")
+	var list []string
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		// Remove quotes if present
+		p = strings.Trim(p, `"'`)
+		if p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 func (a *Annotation) GetParamStringListOrDefault(name string, def []string, aliases ...string) []string {
 	if v, ok := a.GetParamStringList(name, aliases...); ok {
 		return v
@@ -268,3 +274,112 @@ func (tags StructTags) GetTagStringListOrDefault(name string, def []string, alia
 	}
 	return def
 }
+
+// ParseParamReference checks if a parameter value is a reference to a constant, e.g. {MaxRetries} or {pkg.MaxRetries}.
+// Returns the referenced name and true if the value is a reference, empty string and false otherwise.
+func ParseParamReference(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+		return "", false
+	}
+	name := strings.TrimSpace(value[1 : len(value)-1])
+	if name == "" {
+		return "", false
+	}
+	for _, part := range strings.Split(name, ".") {
+		if !isIdentifier(part) {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// ResolveParamReferences replaces the parameter values that reference constants (e.g. default={MaxRetries})
+// with the value returned by resolve, so generated output stays in sync with the Go constants.
+// Returns the sorted names of the references that could not be resolved, their values are left untouched.
+func (a *Annotation) ResolveParamReferences(resolve func(name string) (string, bool)) []string {
+	var unresolved []string
+	for k, v := range a.Params {
+		name, ok := ParseParamReference(v)
+		if !ok {
+			continue
+		}
+		if val, found := resolve(name); found {
+			a.Params[k] = val
+		} else {
+			unresolved = append(unresolved, name)
+		}
+	}
+	slices.Sort(unresolved)
+	return unresolved
+}
+
+// isIdentifier checks if a string is a valid Go identifier
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, ch := range s {
+		isLetter := (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+		isDigit := ch >= '0' && ch <= '9'
+		if !isLetter && (i == 0 || !isDigit) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetGroups returns the visibility groups declared in the "groups" parameter of an annotation,
+// e.g. @schema(groups="public,admin")
+func (a *Annotation) GetGroups() []string {
+	if !a.HasParam("groups", "group") {
+		return nil
+	}
+	groups, _ := a.GetParamStringList("groups", "group")
+	return groups
+}
+
+// InGroup checks if an annotation is visible for the active group. Annotations without groups
+// are visible for every group, and an empty active group sees every annotation.
+func (a *Annotation) InGroup(active string) bool {
+	groups := a.GetGroups()
+	if active == "" || len(groups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		if strings.EqualFold(g, active) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterAnnotationsByGroup returns the annotations visible for the active group
+func FilterAnnotationsByGroup(annotations []Annotation, active string) []Annotation {
+	var filtered []Annotation
+	for _, ann := range annotations {
+		if ann.InGroup(active) {
+			filtered = append(filtered, ann)
+		}
+	}
+	return filtered
+}
+
+// IsVisibleInGroup checks if an element is part of the active group variant: elements whose annotations
+// declare no groups are always visible, otherwise one of the annotations must list the active group
+func IsVisibleInGroup(annotations []Annotation, active string) bool {
+	if active == "" {
+		return true
+	}
+	declared := false
+	for _, ann := range annotations {
+		if len(ann.GetGroups()) == 0 {
+			continue
+		}
+		declared = true
+		if ann.InGroup(active) {
+			return true
+		}
+	}
+	return !declared
+}
@@ -0,0 +1,236 @@
+package gonnotation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy converts Go names into the external names emitted by generators
+type NamingStrategy interface {
+	Apply(goName string) string
+}
+
+// NamingStrategyFunc adapts a function to the NamingStrategy interface
+type NamingStrategyFunc func(goName string) string
+
+func (f NamingStrategyFunc) Apply(goName string) string {
+	return f(goName)
+}
+
+// NamingCase represents a built-in naming case
+type NamingCase string
+
+const (
+	PreserveCase NamingCase = "preserve" // Keep the Go name as is
+	PascalCase   NamingCase = "pascal"   // UserProfile
+	CamelCase    NamingCase = "camel"    // userProfile
+	SnakeCase    NamingCase = "snake"    // user_profile
+	KebabCase    NamingCase = "kebab"    // user-profile
+	ScreamCase   NamingCase = "scream"   // USER_PROFILE
+)
+
+// CaseNamingStrategy is the built-in naming strategy: it converts the name to a case,
+// keeps the abbreviations in upper case (pascal and camel only) and adds a prefix and suffix
+type CaseNamingStrategy struct {
+	Case          NamingCase `yaml:"case" json:"case"`
+	Prefix        string     `yaml:"prefix" json:"prefix"`
+	Suffix        string     `yaml:"suffix" json:"suffix"`
+	Abbreviations []string   `yaml:"abbreviations" json:"abbreviations"` // Words kept upper case, e.g. "ID", "URL", "HTTP"
+}
+
+// NewNamingStrategy returns the built-in naming strategy for a case name (e.g. "camel", "snake_case")
+// Returns false if the case is unknown
+func NewNamingStrategy(name string) (*CaseNamingStrategy, bool) {
+	c, ok := ParseNamingCase(name)
+	if !ok {
+		return nil, false
+	}
+	return &CaseNamingStrategy{Case: c}, true
+}
+
+// ParseNamingCase parses a naming case name, accepting the common spellings
+// e.g. "camel", "camelCase", "snake_case", "kebab-case"
+func ParseNamingCase(name string) (NamingCase, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("_", "", "-", "", " ", "").Replace(name)
+	name = strings.TrimSuffix(name, "case")
+	switch name {
+	case "", "preserve", "none", "go":
+		return PreserveCase, true
+	case "pascal", "uppercamel":
+		return PascalCase, true
+	case "camel", "lowercamel":
+		return CamelCase, true
+	case "snake":
+		return SnakeCase, true
+	case "kebab":
+		return KebabCase, true
+	case "scream", "screamingsnake", "constant":
+		return ScreamCase, true
+	}
+	return "", false
+}
+
+// Apply converts the Go name using the strategy
+func (s *CaseNamingStrategy) Apply(goName string) string {
+	if goName == "" {
+		return ""
+	}
+	return s.Prefix + s.convert(goName) + s.Suffix
+}
+
+func (s *CaseNamingStrategy) convert(goName string) string {
+	if s.Case == PreserveCase || s.Case == "" {
+		return goName
+	}
+
+	words := SplitNameWords(goName)
+	for i, w := range words {
+		switch s.Case {
+		case PascalCase, CamelCase:
+			if abbr, ok := s.abbreviation(w); ok && (i > 0 || s.Case == PascalCase) {
+				words[i] = abbr
+			} else if i == 0 && s.Case == CamelCase {
+				words[i] = strings.ToLower(w)
+			} else {
+				words[i] = capitalize(w)
+			}
+		case SnakeCase, KebabCase:
+			words[i] = strings.ToLower(w)
+		case ScreamCase:
+			words[i] = strings.ToUpper(w)
+		}
+	}
+
+	switch s.Case {
+	case SnakeCase, ScreamCase:
+		return strings.Join(words, "_")
+	case KebabCase:
+		return strings.Join(words, "-")
+	}
+	return strings.Join(words, "")
+}
+
+// abbreviation returns the configured spelling of a word if it is an abbreviation
+func (s *CaseNamingStrategy) abbreviation(word string) (string, bool) {
+	for _, abbr := range s.Abbreviations {
+		if strings.EqualFold(abbr, word) {
+			return abbr, true
+		}
+	}
+	return "", false
+}
+
+// SplitNameWords splits a Go identifier into words, e.g. "HTTPServerID" results in ["HTTP", "Server", "ID"]
+// and "user_profile-v2" in ["user", "profile", "v2"]
+func SplitNameWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '_' || r == '-' || r == ' ' || r == '.' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start {
+			continue
+		}
+		prev := runes[i-1]
+		// Word boundary on lower->Upper (userName) or on the last upper of an acronym followed by lower (HTTPServer)
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// capitalize upper cases the first letter of a word and lower cases the rest
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) == 0 {
+		return word
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// RenameMap holds explicit Go name to output name tables, useful when wire names must diverge from
+// Go names without touching every comment. Renames are applied before the naming strategies.
+type RenameMap struct {
+	Types  map[string]string `yaml:"types" json:"types"`   // Go type name (struct, enum or interface) -> output name
+	Fields map[string]string `yaml:"fields" json:"fields"` // Go field name, or Type.Field for a single type -> output name
+}
+
+// TypeName returns the output name of a renamed type
+func (m RenameMap) TypeName(goName string) (string, bool) {
+	name, ok := m.Types[goName]
+	return name, ok
+}
+
+// FieldName returns the output name of a renamed field, Type.Field entries take precedence over Field ones
+func (m RenameMap) FieldName(typeName string, goName string) (string, bool) {
+	if typeName != "" {
+		if name, ok := m.Fields[typeName+"."+goName]; ok {
+			return name, true
+		}
+	}
+	name, ok := m.Fields[goName]
+	return name, ok
+}
+
+// NameResolver resolves external names using the rename tables, a default naming strategy
+// and optional per placement strategies (e.g. camelCase fields and PascalCase types)
+type NameResolver struct {
+	Renames    RenameMap
+	Default    NamingStrategy
+	Placements map[AnnotationPlacement]NamingStrategy
+}
+
+// ResolveName converts the Go name of an element placed on the given placement into its external name
+func (r *NameResolver) ResolveName(placement AnnotationPlacement, goName string) string {
+	if r == nil {
+		return goName
+	}
+	switch placement {
+	case StructAnnotationPlacement, EnumAnnotationPlacement, InterfaceAnnotationPlacement:
+		if name, ok := r.Renames.TypeName(goName); ok {
+			return name
+		}
+	case FieldAnnotationPlacement:
+		if name, ok := r.Renames.FieldName("", goName); ok {
+			return name
+		}
+	}
+	return r.applyStrategy(placement, goName)
+}
+
+// ResolveFieldName converts the Go name of a field of the given type into its external name
+func (r *NameResolver) ResolveFieldName(typeName string, goName string) string {
+	if r == nil {
+		return goName
+	}
+	if name, ok := r.Renames.FieldName(typeName, goName); ok {
+		return name
+	}
+	return r.applyStrategy(FieldAnnotationPlacement, goName)
+}
+
+func (r *NameResolver) applyStrategy(placement AnnotationPlacement, goName string) string {
+	if s, ok := r.Placements[placement]; ok && s != nil {
+		return s.Apply(goName)
+	}
+	if r.Default != nil {
+		return r.Default.Apply(goName)
+	}
+	return goName
+}
@@ -1,7 +1,9 @@
 package gonnotation
 
 import (
+	"slices"
 	"strings"
+	"unicode"
 )
 
 // StructTags represents parsed struct tags
@@ -44,23 +46,76 @@ type StructTags map[string]string
 // 	return annotations
 // }
 
+// ParseOptions configures how annotations are parsed from comment text, the zero value keeps the default behavior
+type ParseOptions struct {
+	// Marker is a required prefix for annotation lines, so annotations don't collide with other tools' @ directives.
+	// For example with "gonnotation:" only lines like "gonnotation: @schema" are parsed. Empty parses every @ line.
+	Marker string `yaml:"marker" json:"marker"`
+	// DuplicatePolicy defines what happens when the same annotation appears more than once on the text
+	DuplicatePolicy DuplicatePolicy `yaml:"duplicatePolicy" json:"duplicatePolicy"`
+	// Aliases maps annotation names to the annotation they stand for across all the plugins,
+	// e.g. {"model": "schema"} parses @model(...) as @schema(...); OriginalName keeps the written name
+	Aliases map[string]string `yaml:"aliases" json:"aliases"`
+	// Specs are used by the duplicate policy: annotations whose spec allows Multiple are never duplicates,
+	// and names are compared following the specs case policy
+	Specs AnnotationSpecs `yaml:"-" json:"-"`
+}
+
+// ParseAnnotationsFromText parses the annotations found in a comment text using the default options
 func ParseAnnotationsFromText(text string) []Annotation {
+	annotations, _ := ParseAnnotationsWithOptions(text, ParseOptions{})
+	return annotations
+}
+
+// ParseAnnotationsWithOptions parses the annotations found in a comment text using the given options.
+// All the problems found are returned as ParseErrors together with the annotations that could be resolved.
+func ParseAnnotationsWithOptions(text string, opts ParseOptions) ([]Annotation, error) {
 	if text == "" {
-		return nil
+		return nil, nil
 	}
 
 	var annotations []Annotation
+	var lines []int
 
-	for _, line := range strings.Split(text, "\n") {
+	for i, line := range strings.Split(text, "\n") {
 		line = strings.TrimSpace(line)
+		if opts.Marker != "" {
+			if !strings.HasPrefix(line, opts.Marker) {
+				continue
+			}
+			line = strings.TrimSpace(strings.TrimPrefix(line, opts.Marker))
+		}
 		if strings.HasPrefix(line, "@") {
 			ann := parseAnnotation(line)
 			if ann.Name != "" {
 				annotations = append(annotations, ann)
+				lines = append(lines, i+1)
 			}
 		}
 	}
 
+	annotations = ResolveAnnotationAliases(annotations, opts.Aliases, opts.Specs.CaseSensitive)
+	annotations, errs := resolveDuplicates(annotations, lines, opts)
+	return annotations, errs.Err()
+}
+
+// ResolveAnnotationAliases renames the annotations found in the aliases map (alias -> annotation name),
+// matching names with the given case policy. The written name is kept in OriginalName.
+func ResolveAnnotationAliases(annotations []Annotation, aliases map[string]string, caseSensitive bool) []Annotation {
+	if len(aliases) == 0 {
+		return annotations
+	}
+
+	normalized := make(map[string]string, len(aliases))
+	for alias, target := range aliases {
+		normalized[NormalizeAnnotationNameWithCase(strings.TrimPrefix(alias, "@"), caseSensitive)] = strings.TrimPrefix(strings.TrimSpace(target), "@")
+	}
+
+	for i := range annotations {
+		if target, ok := normalized[NormalizeAnnotationNameWithCase(annotations[i].Name, caseSensitive)]; ok {
+			annotations[i].Name = target
+		}
+	}
 	return annotations
 }
 
@@ -72,206 +127,318 @@ func parseAnnotation(line string) Annotation {
 	}
 
 	line = strings.TrimPrefix(line, "@")
-	parenIdx := strings.Index(line, "(")
+
+	// The name ends at the first space or opening parenthesis
+	nameEnd := strings.IndexFunc(line, func(r rune) bool {
+		return r == '(' || unicode.IsSpace(r)
+	})
+	if nameEnd == -1 {
+		nameEnd = len(line)
+	}
+	ann.Name = line[:nameEnd]
+	ann.OriginalName = ann.Name
+	rest := strings.TrimSpace(line[nameEnd:])
 
 	// Format 1: @name(key:value, key2:value2)
-	if parenIdx != -1 {
-		ann.Name = strings.TrimSpace(line[:parenIdx])
-		paramsStr := line[parenIdx+1:]
-		if endIdx := strings.LastIndex(paramsStr, ")"); endIdx != -1 {
-			paramsStr = paramsStr[:endIdx]
-		}
-		ann.Params = parseParamsParentheses(paramsStr)
+	if strings.HasPrefix(rest, "(") {
+		ann.Params = parseParamsParentheses(rest[1:closingParenIndex(rest)])
 		return ann
 	}
 
 	// Format 2: @name key="value" key2="value2" (space-separated)
 	// or Format 3: @name (no parameters)
-	parts := splitAnnotationParts(line)
-	if len(parts) == 0 {
-		return ann
-	}
-
-	ann.Name = strings.TrimSpace(parts[0])
-
-	// If there are more parts, parse them as space-separated key="value" pairs
-	if len(parts) > 1 {
-		ann.Params = parseParamsSpaceSeparated(parts[1:])
+	if rest != "" {
+		ann.Params = parseParamsSpaceSeparated(splitParams(rest, unicode.IsSpace))
 	}
 
 	return ann
 }
 
-// parseParamsParentheses parses key:value pairs from parentheses format: (key:value, key2:value2)
-func parseParamsParentheses(s string) map[string]string {
-	params := make(map[string]string)
-	var parts []string
-	var current strings.Builder
-	inQuotes := false
-	quoteChar := rune(0)
-	bracketDepth := 0
+// positionalParam is a value written without a key, Flag is true for the unquoted ones parsed as boolean flags
+type positionalParam struct {
+	Value string
+	Flag  bool
+}
 
-	for _, ch := range s {
-		if ch == '"' || ch == '\'' {
-			if !inQuotes {
-				inQuotes = true
-				quoteChar = ch
-			} else if ch == quoteChar {
-				inQuotes = false
+// positionalParams returns the values of an annotation written without a key in declaration order,
+// re-reading its RawText since the params map loses the order. Annotations without a parseable RawText
+// (built by hand or from struct tags) return the default param followed by their flags sorted by name.
+// Flags are only returned while they are still set to true, transformers could have changed the params.
+func positionalParams(ann Annotation) []positionalParam {
+	var params []positionalParam
+	text := strings.TrimSpace(ann.RawText)
+	if !strings.HasPrefix(text, "@") {
+		if v, ok := ann.Params[""]; ok {
+			params = append(params, positionalParam{Value: v})
+		}
+		var flags []string
+		for k, v := range ann.Params {
+			if k != "" && v == "true" {
+				flags = append(flags, k)
 			}
-			current.WriteRune(ch)
-		} else if ch == '[' && !inQuotes {
-			bracketDepth++
-			current.WriteRune(ch)
-		} else if ch == ']' && !inQuotes {
-			bracketDepth--
-			current.WriteRune(ch)
-		} else if ch == ',' && !inQuotes && bracketDepth == 0 {
-			parts = append(parts, current.String())
-			current.Reset()
-		} else {
-			current.WriteRune(ch)
 		}
-	}
-	if current.Len() > 0 {
-		parts = append(parts, current.String())
+		slices.Sort(flags)
+		for _, f := range flags {
+			params = append(params, positionalParam{Value: f, Flag: true})
+		}
+		return params
 	}
 
-	positionalIdx := 0
+	text = text[1:]
+	nameEnd := strings.IndexFunc(text, func(r rune) bool {
+		return r == '(' || unicode.IsSpace(r)
+	})
+	if nameEnd == -1 {
+		nameEnd = len(text)
+	}
+	rest := strings.TrimSpace(text[nameEnd:])
+	var parts []string
+	if strings.HasPrefix(rest, "(") {
+		parts = splitParams(rest[1:closingParenIndex(rest)], func(r rune) bool { return r == ',' })
+	} else {
+		parts = splitParams(rest, unicode.IsSpace)
+	}
 	for _, part := range parts {
-		part = strings.TrimSpace(part)
-
-		// Support both : and = as separators
-		sepIdx := -1
-		for i, ch := range part {
-			if (ch == ':' || ch == '=') && !isInQuotes(part, i) {
-				sepIdx = i
-				break
-			}
+		if _, _, ok := splitKeyValue(part); ok {
+			continue
+		}
+		if value, wasQuoted := unquoteValue(part); wasQuoted || !isBooleanFlag(value) {
+			params = append(params, positionalParam{Value: value})
+		} else if ann.Params[part] == "true" {
+			params = append(params, positionalParam{Value: part, Flag: true})
 		}
+	}
+	return params
+}
 
-		if sepIdx == -1 {
-			// No separator - could be a boolean flag or positional argument
-			// Check if the original part (before removing quotes) was quoted
-			wasQuoted := (strings.HasPrefix(part, `"`) && strings.HasSuffix(part, `"`)) ||
-				(strings.HasPrefix(part, `'`) && strings.HasSuffix(part, `'`))
-			value := strings.Trim(part, `"'`)
-
-			// If it was quoted, treat it as a positional argument (not a boolean flag)
-			// Otherwise, check if it looks like a boolean flag (simple identifier)
-			if !wasQuoted && isBooleanFlag(value) {
-				params[value] = "true"
-			} else {
-				// Positional argument
-				if positionalIdx == 0 {
-					// First positional argument uses empty key (default parameter)
-					params[""] = value
-				} else {
-					// Subsequent positional arguments are stored as indexed values
-					// This allows validation to accept them as valid array elements
-					params[""] = params[""] + "," + value
-				}
-				positionalIdx++
-			}
+// closingParenIndex returns the index of the parenthesis closing the one at the start of s,
+// ignoring the ones inside quotes and nested brackets. Returns len(s) if it's not closed.
+func closingParenIndex(s string) int {
+	l := paramLexer{}
+	for i, ch := range s {
+		if i == 0 {
 			continue
 		}
+		if l.next(ch) && ch == ')' && l.depth == 0 {
+			return i
+		}
+	}
+	return len(s)
+}
 
-		key := strings.TrimSpace(part[:sepIdx])
-		value := strings.TrimSpace(part[sepIdx+1:])
-		value = strings.Trim(value, `"'`)
-		params[key] = value
+// paramLexer tracks the quoting and nesting state while scanning annotation params.
+// Quotes (", ' and ` for raw strings) only start at the beginning of a key or value, so apostrophes
+// inside unquoted text (e.g. it's) are literal. Inside " and ' quotes a backslash escapes the next char.
+type paramLexer struct {
+	quote   rune // current quote char, 0 outside quotes
+	escaped bool // the previous char was a backslash inside quotes
+	depth   int  // nesting depth of (), [] and {} outside quotes
+	prev    rune // previous non-space char outside quotes, 0 at the start of a part
+}
+
+// next advances the lexer with ch and reports whether ch is outside quotes (a structural char)
+func (l *paramLexer) next(ch rune) bool {
+	if l.quote != 0 {
+		switch {
+		case l.escaped:
+			l.escaped = false
+		case ch == '\\' && l.quote != '`':
+			l.escaped = true
+		case ch == l.quote:
+			l.quote = 0
+			l.prev = ch
+		}
+		return false
 	}
 
-	return params
+	switch ch {
+	case '"', '\'', '`':
+		if l.prev == 0 || strings.ContainsRune(":=[{(,", l.prev) {
+			l.quote = ch
+			return false
+		}
+	case '(', '[', '{':
+		l.depth++
+	case ')', ']', '}':
+		if l.depth > 0 {
+			l.depth--
+		}
+	}
+	if !unicode.IsSpace(ch) {
+		l.prev = ch
+	}
+	return true
 }
 
-// splitAnnotationParts splits annotation line into parts, respecting quotes
-func splitAnnotationParts(line string) []string {
+// splitParams splits s on the separator chars found outside quotes and brackets, empty parts are skipped
+func splitParams(s string, isSep func(rune) bool) []string {
 	var parts []string
 	var current strings.Builder
-	inQuotes := false
-	quoteChar := rune(0)
-
-	for _, ch := range line {
-		if ch == '"' || ch == '\'' {
-			if !inQuotes {
-				inQuotes = true
-				quoteChar = ch
-			} else if ch == quoteChar {
-				inQuotes = false
-			}
-			current.WriteRune(ch)
-		} else if ch == ' ' && !inQuotes {
-			if current.Len() > 0 {
-				parts = append(parts, current.String())
-				current.Reset()
-			}
-		} else {
-			current.WriteRune(ch)
+	l := paramLexer{}
+
+	flush := func() {
+		if part := strings.TrimSpace(current.String()); part != "" {
+			parts = append(parts, part)
 		}
+		current.Reset()
+		l.prev = 0
 	}
 
-	if current.Len() > 0 {
-		parts = append(parts, current.String())
+	for _, ch := range s {
+		depth := l.depth
+		if l.next(ch) && depth == 0 && isSep(ch) {
+			flush()
+			continue
+		}
+		current.WriteRune(ch)
 	}
+	flush()
 
 	return parts
 }
 
-// parseParamsSpaceSeparated parses space-separated key="value" pairs
-func parseParamsSpaceSeparated(parts []string) map[string]string {
-	params := make(map[string]string)
-
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
+// splitKeyValue splits a param on its first ':' or '=' outside quotes and brackets.
+// Returns false if there's no separator, the text before it isn't a valid key or the ':'
+// starts a URL authority (://), so positional values like "https://x" aren't split.
+func splitKeyValue(part string) (string, string, bool) {
+	l := paramLexer{}
+	for i, ch := range part {
+		depth := l.depth
+		if !l.next(ch) || depth != 0 || (ch != ':' && ch != '=') {
 			continue
 		}
+		key := strings.TrimSpace(part[:i])
+		if !isParamKey(key) || (ch == ':' && strings.HasPrefix(part[i+1:], "//")) {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(part[i+1:]), true
+	}
+	return "", "", false
+}
 
-		// Support both = and : as separators
-		sepIdx := -1
-		for i, ch := range part {
-			if (ch == '=' || ch == ':') && !isInQuotes(part, i) {
-				sepIdx = i
-				break
+// unquoteValue removes the quotes of a quoted value resolving its escape sequences.
+// Raw strings (`...`) are returned as is. Returns false if the value isn't quoted.
+func unquoteValue(v string) (string, bool) {
+	if len(v) < 2 || v[0] != v[len(v)-1] {
+		return v, false
+	}
+	inner := v[1 : len(v)-1]
+	switch v[0] {
+	case '`':
+		return inner, true
+	case '"', '\'':
+		if !strings.ContainsRune(inner, '\\') {
+			return inner, true
+		}
+		var b strings.Builder
+		escaped := false
+		for _, ch := range inner {
+			if escaped {
+				switch ch {
+				case 'n':
+					b.WriteRune('\n')
+				case 't':
+					b.WriteRune('\t')
+				case 'r':
+					b.WriteRune('\r')
+				case '"', '\'', '\\':
+					b.WriteRune(ch)
+				default:
+					// Unknown escapes are kept as written, e.g. regex patterns like \d
+					b.WriteRune('\\')
+					b.WriteRune(ch)
+				}
+				escaped = false
+				continue
+			}
+			if ch == '\\' {
+				escaped = true
+				continue
 			}
+			b.WriteRune(ch)
+		}
+		if escaped {
+			b.WriteRune('\\')
+		}
+		return b.String(), true
+	}
+	return v, false
+}
+
+// parseParamsParentheses parses key:value pairs from parentheses format: (key:value, key2:value2)
+func parseParamsParentheses(s string) map[string]string {
+	params := make(map[string]string)
+
+	positionalIdx := 0
+	for _, part := range splitParams(s, func(r rune) bool { return r == ',' }) {
+		key, value, ok := splitKeyValue(part)
+		if ok {
+			params[key], _ = unquoteValue(value)
+			continue
 		}
 
-		if sepIdx == -1 {
-			// No separator, treat as boolean flag
-			params[part] = "true"
+		// No separator - could be a boolean flag or positional argument
+		// If it was quoted, treat it as a positional argument (not a boolean flag)
+		// Otherwise, check if it looks like a boolean flag (simple identifier)
+		value, wasQuoted := unquoteValue(part)
+		if !wasQuoted && isBooleanFlag(value) {
+			params[value] = "true"
 			continue
 		}
 
-		key := strings.TrimSpace(part[:sepIdx])
-		value := strings.TrimSpace(part[sepIdx+1:])
-		value = strings.Trim(value, `"'`)
-		params[key] = value
+		// Positional argument
+		if positionalIdx == 0 {
+			// First positional argument uses empty key (default parameter)
+			params[""] = value
+		} else {
+			// Subsequent positional arguments are stored as indexed values
+			// This allows validation to accept them as valid array elements
+			params[""] = params[""] + "," + value
+		}
+		positionalIdx++
 	}
 
 	return params
 }
 
-// isInQuotes checks if a character at given index is inside quotes
-func isInQuotes(s string, idx int) bool {
-	inQuotes := false
-	quoteChar := rune(0)
+// parseParamsSpaceSeparated parses space-separated key="value" pairs
+func parseParamsSpaceSeparated(parts []string) map[string]string {
+	params := make(map[string]string)
 
-	for i, ch := range s {
-		if i >= idx {
-			break
+	for _, part := range parts {
+		key, value, ok := splitKeyValue(part)
+		if ok {
+			params[key], _ = unquoteValue(value)
+			continue
 		}
-		if ch == '"' || ch == '\'' {
-			if !inQuotes {
-				inQuotes = true
-				quoteChar = ch
-			} else if ch == quoteChar {
-				inQuotes = false
+
+		// No separator, a quoted value or one that can't be a flag (e.g. a URL) is the default param,
+		// otherwise treat it as boolean flag
+		if value, wasQuoted := unquoteValue(part); wasQuoted || !isBooleanFlag(value) {
+			if _, exists := params[""]; exists {
+				params[""] = params[""] + "," + value
+			} else {
+				params[""] = value
 			}
+			continue
 		}
+		params[part] = "true"
 	}
 
-	return inQuotes
+	return params
+}
+
+// isParamKey checks if a string can be a param key: letters, digits, '_', '-' and '.'
+func isParamKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, ch := range s {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' && ch != '-' && ch != '.' {
+			return false
+		}
+	}
+	return true
 }
 
 // isBooleanFlag checks if a string looks like a boolean flag (simple identifier)
@@ -1,6 +1,7 @@
 package gonnotation
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -143,3 +144,997 @@ func TestAnnotationHelpers(t *testing.T) {
 		t.Errorf("GetParamStringList failed: got %v, %v", list, ok)
 	}
 }
+
+func TestAnnotationSpecMatches(t *testing.T) {
+	for _, name := range []string{"union", "Union", "oneOf", "ONEOF", " union "} {
+		if !UnionAnnotationSpec.Matches(name) {
+			t.Errorf("UnionAnnotationSpec.Matches(%q) = false, want true", name)
+		}
+	}
+	if UnionAnnotationSpec.Matches("unions") || UnionAnnotationSpec.Matches("") {
+		t.Error("UnionAnnotationSpec.Matches() matched an unrelated name")
+	}
+}
+
+func TestAnnotationsFromStructTag(t *testing.T) {
+	mappings := []TagAnnotationMapping{
+		{Tag: "validate", Annotation: "field"},
+		{Tag: "gorm", Option: "primaryKey", Annotation: "id"},
+		{Tag: "gorm", Option: "column", Annotation: "column"},
+	}
+
+	anns := AnnotationsFromStructTag(`json:"id" validate:"required,max=10" gorm:"primaryKey;column:user_id"`, mappings)
+	if len(anns) != 3 {
+		t.Fatalf("AnnotationsFromStructTag() returned %d annotations, want 3", len(anns))
+	}
+
+	if anns[0].Name != "field" || anns[0].OriginalName != "field" || anns[0].Params["required"] != "true" || anns[0].Params["max"] != "10" {
+		t.Errorf("validate mapping = %+v", anns[0])
+	}
+	if anns[1].Name != "id" || anns[1].OriginalName != "id" || len(anns[1].Params) != 0 {
+		t.Errorf("primaryKey mapping = %+v", anns[1])
+	}
+	if anns[2].Name != "column" || anns[2].Params[""] != "user_id" {
+		t.Errorf("column mapping = %+v", anns[2])
+	}
+
+	anns = AnnotationsFromStructTag(`validate:"required,min=1,dive,min=3,email"`, []TagAnnotationMapping{{Tag: "validate", Annotation: "validate"}})
+	if len(anns) != 1 || anns[0].Params["min"] != "1" || anns[0].HasParam("dive") || anns[0].HasParam("email") {
+		t.Fatalf("AnnotationsFromStructTag(dive) = %+v", anns)
+	}
+	if c := ParseConstraints(anns, "", ConstraintKindCollection); !c.Required || c.MinItems == nil || *c.MinItems != 1 || c.Min != nil || c.Format != "" {
+		t.Errorf("ParseConstraints(AnnotationsFromStructTag(dive)) = %+v", c)
+	}
+
+	if anns := AnnotationsFromStructTag(`json:"id"`, mappings); len(anns) != 0 {
+		t.Errorf("AnnotationsFromStructTag() without mapped tags = %+v, want none", anns)
+	}
+}
+
+func TestResolveParamReferences(t *testing.T) {
+	ann := parseAnnotation(`@field(default={MaxRetries}, max={limits.Max}, example:"{not a ref}", min={Missing})`)
+	consts := map[string]string{
+		"MaxRetries": "3",
+		"limits.Max": "100",
+	}
+
+	unresolved := ann.ResolveParamReferences(func(name string) (string, bool) {
+		v, ok := consts[name]
+		return v, ok
+	})
+
+	if ann.Params["default"] != "3" || ann.Params["max"] != "100" {
+		t.Errorf("ResolveParamReferences() params = %v", ann.Params)
+	}
+	if ann.Params["example"] != "{not a ref}" {
+		t.Errorf("ResolveParamReferences() should not touch non references, got %q", ann.Params["example"])
+	}
+	if len(unresolved) != 1 || unresolved[0] != "Missing" || ann.Params["min"] != "{Missing}" {
+		t.Errorf("ResolveParamReferences() unresolved = %v, min = %q", unresolved, ann.Params["min"])
+	}
+
+	ann = parseAnnotation(`@field(a={Zeta}, b={Alpha}, c={Mid}, d={Beta})`)
+	unresolved = ann.ResolveParamReferences(func(string) (string, bool) { return "", false })
+	if len(unresolved) != 4 || unresolved[0] != "Alpha" || unresolved[1] != "Beta" || unresolved[2] != "Mid" || unresolved[3] != "Zeta" {
+		t.Errorf("ResolveParamReferences() unresolved = %v, want them sorted", unresolved)
+	}
+}
+
+func TestGetDeprecation(t *testing.T) {
+	anns := ParseAnnotationsFromText("Some description\n@schema\n@Deprecated(\"use NewUser instead\", since:\"v2.0.0\")")
+	info, ok := GetDeprecation(anns)
+	if !ok {
+		t.Fatal("GetDeprecation() did not find the annotation")
+	}
+	if info.Reason != "use NewUser instead" || info.Since != "v2.0.0" {
+		t.Errorf("GetDeprecation() = %+v", info)
+	}
+
+	if _, ok := GetDeprecation(ParseAnnotationsFromText("@schema")); ok {
+		t.Error("GetDeprecation() found an annotation that is not present")
+	}
+}
+
+func TestGetExamples(t *testing.T) {
+	anns := ParseAnnotationsFromText(`@example("john")
+@example(42)
+@example(true)
+@example({"id": 1, "tags": ["a", "b"]})
+@example 'plain'`)
+
+	examples := GetExamples(anns)
+	if len(examples) != 5 {
+		t.Fatalf("GetExamples() returned %d examples, want 5", len(examples))
+	}
+	if examples[0] != "john" || examples[1] != 42 || examples[2] != true || examples[4] != "plain" {
+		t.Errorf("GetExamples() = %#v", examples)
+	}
+	if doc, ok := examples[3].(map[string]any); !ok || doc["id"] != float64(1) {
+		t.Errorf("GetExamples() JSON example = %#v", examples[3])
+	}
+
+	// Aliased annotations keep the name as written, the payload must still be found
+	anns, _ = ParseAnnotationsWithOptions(`@Sample({"id": 2})`, ParseOptions{Aliases: map[string]string{"sample": "example"}})
+	if examples := GetExamples(anns); len(examples) != 1 {
+		t.Errorf("GetExamples(alias) = %#v", examples)
+	} else if doc, ok := examples[0].(map[string]any); !ok || doc["id"] != float64(2) {
+		t.Errorf("GetExamples(alias) = %#v", examples[0])
+	}
+
+	for _, raw := range []string{"NaN", "Inf", "-Inf", "Infinity", "+infinity"} {
+		if got := ParseExampleValue(raw); got != raw {
+			t.Errorf("ParseExampleValue(%q) = %#v, want the string", raw, got)
+		}
+	}
+	if got := ParseExampleValue("1.5"); got != 1.5 {
+		t.Errorf("ParseExampleValue(1.5) = %#v", got)
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	anns := ParseAnnotationsFromText(`@field(max:20, pattern:"^[a-z]+$", enum:[a, b])`)
+
+	c := ParseConstraints(anns, "required,min=1,max=10,oneof=red green,email", ConstraintKindNumber)
+	if !c.Required || c.Min == nil || *c.Min != 1 || c.Format != "email" {
+		t.Errorf("ParseConstraints() from tags = %+v", c)
+	}
+	if c.Max == nil || *c.Max != 20 {
+		t.Errorf("ParseConstraints() annotation should override max, got %v", c.Max)
+	}
+	if c.Pattern != "^[a-z]+$" || len(c.Enum) != 2 || c.Enum[0] != "a" {
+		t.Errorf("ParseConstraints() from annotation = %+v", c)
+	}
+
+	c = ParseConstraints(nil, "min=2,lt=10", ConstraintKindString)
+	if c.MinLength == nil || *c.MinLength != 2 || c.MaxLength == nil || *c.MaxLength != 9 || c.Min != nil || c.ExclusiveMax != nil {
+		t.Errorf("ParseConstraints(string) = %+v", c)
+	}
+
+	c = ParseConstraints(nil, "required,min=1,max=5,unique,dive,min=3,max=50,email", ConstraintKindCollection)
+	if c.MinItems == nil || *c.MinItems != 1 || c.MaxItems == nil || *c.MaxItems != 5 || !c.UniqueItems {
+		t.Errorf("ParseConstraints(collection) = %+v", c)
+	}
+	if c.MinLength != nil || c.MaxLength != nil || c.Format != "" {
+		t.Errorf("ParseConstraints(collection) merged the dive rules = %+v", c)
+	}
+
+	// @validate params follow the struct tag rules, @field ones are explicit
+	c = ParseConstraints(ParseAnnotationsFromText("@validate(min=2, max=10)"), "", ConstraintKindString)
+	if c.MinLength == nil || *c.MinLength != 2 || c.MaxLength == nil || *c.MaxLength != 10 || c.Min != nil || c.Max != nil {
+		t.Errorf("ParseConstraints(@validate, string) = %+v", c)
+	}
+	c = ParseConstraints(ParseAnnotationsFromText("@validate(gt=0, lte=3)"), "max=5", ConstraintKindCollection)
+	if c.MinItems == nil || *c.MinItems != 1 || c.MaxItems == nil || *c.MaxItems != 3 || c.Min != nil || c.ExclusiveMin != nil {
+		t.Errorf("ParseConstraints(@validate, collection) = %+v", c)
+	}
+	c = ParseConstraints(ParseAnnotationsFromText("@field(max:10)"), "", ConstraintKindString)
+	if c.Max == nil || *c.Max != 10 || c.MaxLength != nil {
+		t.Errorf("ParseConstraints(@field, string) = %+v", c)
+	}
+
+	if c := ParseConstraints(nil, "required,min=1,rgb|rgba", ConstraintKindUnknown); !c.Required || c.Min != nil || c.MinLength != nil || c.MinItems != nil {
+		t.Errorf("ParseConstraints(unknown kind) = %+v", c)
+	}
+
+	if c := ParseConstraints(nil, "", ConstraintKindUnknown); !c.IsEmpty() {
+		t.Errorf("ParseConstraints() without sources = %+v, want empty", c)
+	}
+}
+
+func TestAnnotationMatcher(t *testing.T) {
+	m := NewAnnotationMatcher("@gql", "type", "input")
+	for _, name := range []string{"gqlType", "GQLINPUT", "type", "Input"} {
+		if !m.Matches(name) || !MatchesAnnotation(name, "@gql", "type", "input") {
+			t.Errorf("Matches(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"gqlEnum", "gql", "typeinput"} {
+		if m.Matches(name) || MatchesAnnotation(name, "@gql", "type", "input") {
+			t.Errorf("Matches(%q) = true, want false", name)
+		}
+	}
+
+	cs := NewAnnotationMatcherWithCase("@gql", true, "Type")
+	for name, want := range map[string]bool{"gqlType": true, "Type": true, "gqltype": false, "GQLType": false, "type": false} {
+		if cs.Matches(name) != want || MatchesAnnotationWithCase(name, "@gql", true, "Type") != want {
+			t.Errorf("case-sensitive Matches(%q) = %v, want %v", name, !want, want)
+		}
+	}
+
+	idx := AnnotationSpecs{Annotations: []AnnotationSpec{{Name: "excludeAll", Aliases: []string{"ignore"}}}}.Index()
+	if spec := idx.Get("IGNORE"); spec == nil || spec.Name != "excludeAll" {
+		t.Errorf("AnnotationSpecIndex.Get() = %v", spec)
+	}
+	if idx.Has("include") {
+		t.Error("AnnotationSpecIndex.Has() found a missing spec")
+	}
+}
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		strategy CaseNamingStrategy
+		input    string
+		want     string
+	}{
+		{CaseNamingStrategy{Case: PascalCase}, "user_profile", "UserProfile"},
+		{CaseNamingStrategy{Case: CamelCase}, "HTTPServerID", "httpServerId"},
+		{CaseNamingStrategy{Case: CamelCase, Abbreviations: []string{"ID", "HTTP"}}, "HTTPServerID", "httpServerID"},
+		{CaseNamingStrategy{Case: PascalCase, Abbreviations: []string{"URL"}}, "imageUrl", "ImageURL"},
+		{CaseNamingStrategy{Case: SnakeCase}, "UserID2Name", "user_id2_name"},
+		{CaseNamingStrategy{Case: KebabCase}, "UserProfile", "user-profile"},
+		{CaseNamingStrategy{Case: ScreamCase}, "maxRetries", "MAX_RETRIES"},
+		{CaseNamingStrategy{Case: PreserveCase, Prefix: "Api", Suffix: "Type"}, "User", "ApiUserType"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.strategy.Apply(tt.input); got != tt.want {
+			t.Errorf("%s.Apply(%q) = %q, want %q", tt.strategy.Case, tt.input, got, tt.want)
+		}
+	}
+
+	camel, _ := NewNamingStrategy("camelCase")
+	r := &NameResolver{
+		Default:    camel,
+		Placements: map[AnnotationPlacement]NamingStrategy{StructAnnotationPlacement: &CaseNamingStrategy{Case: PascalCase}},
+	}
+	if got := r.ResolveName(FieldAnnotationPlacement, "FirstName"); got != "firstName" {
+		t.Errorf("ResolveName(field) = %q", got)
+	}
+	if got := r.ResolveName(StructAnnotationPlacement, "user_profile"); got != "UserProfile" {
+		t.Errorf("ResolveName(struct) = %q", got)
+	}
+
+	r.Renames = RenameMap{
+		Types:  map[string]string{"User": "Account"},
+		Fields: map[string]string{"CreatedAt": "created", "User.Email": "mail"},
+	}
+	if got := r.ResolveName(StructAnnotationPlacement, "User"); got != "Account" {
+		t.Errorf("ResolveName(renamed struct) = %q", got)
+	}
+	if got := r.ResolveFieldName("User", "Email"); got != "mail" {
+		t.Errorf("ResolveFieldName(User.Email) = %q", got)
+	}
+	if got := r.ResolveFieldName("Order", "Email"); got != "email" {
+		t.Errorf("ResolveFieldName(Order.Email) = %q", got)
+	}
+	if got := r.ResolveFieldName("Order", "CreatedAt"); got != "created" {
+		t.Errorf("ResolveFieldName(Order.CreatedAt) = %q", got)
+	}
+}
+
+func TestResolveFieldTagName(t *testing.T) {
+	tests := []struct {
+		tag      string
+		priority []string
+		want     FieldTagName
+	}{
+		{`json:"user_name,omitempty" yaml:"userName"`, nil, FieldTagName{Name: "user_name", Tag: "json", OmitEmpty: true}},
+		{`yaml:"userName"`, nil, FieldTagName{Name: "userName", Tag: "yaml"}},
+		{`json:",omitempty"`, nil, FieldTagName{Name: "UserName", Tag: "json", OmitEmpty: true}},
+		{`json:"-"`, nil, FieldTagName{Name: "UserName", Tag: "json", Skip: true}},
+		{`json:"-,"`, nil, FieldTagName{Name: "-", Tag: "json"}},
+		{`json:"user_name" gql:"name"`, []string{"gql", "json"}, FieldTagName{Name: "name", Tag: "gql"}},
+		{``, nil, FieldTagName{Name: "UserName"}},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveFieldTagName("UserName", tt.tag, tt.priority...); got != tt.want {
+			t.Errorf("ResolveFieldTagName(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestScalarMap(t *testing.T) {
+	scalars := ScalarMap{}
+	scalars.Set("time.Time", "", ScalarMapping{Type: "string", Format: "date-time"})
+	scalars.AddFromAnnotations("uuid.UUID", ParseAnnotationsFromText("@scalar(\"string\", format:\"uuid\")\n@scalar(type:\"UUID\", plugin:\"graphql\")"))
+
+	if m, ok := scalars.ScalarFor("time.Time", "openapi"); !ok || m.Format != "date-time" {
+		t.Errorf("ScalarFor(time.Time) = %+v, %v", m, ok)
+	}
+	if m, ok := scalars.ScalarFor("uuid.UUID", "GraphQL"); !ok || m.Type != "UUID" {
+		t.Errorf("ScalarFor(uuid.UUID, graphql) = %+v, %v", m, ok)
+	}
+	if m, ok := scalars.ScalarFor("uuid.UUID", "openapi"); !ok || m.Type != "string" || m.Format != "uuid" {
+		t.Errorf("ScalarFor(uuid.UUID, openapi) = %+v, %v", m, ok)
+	}
+	if _, ok := scalars.ScalarFor("decimal.Decimal", "openapi"); ok {
+		t.Error("ScalarFor() found a missing type")
+	}
+
+	var zero ScalarMap
+	zero.AddFromAnnotations("decimal.Decimal", ParseAnnotationsFromText(`@scalar("string", format:"decimal", plugin:" OpenAPI ")`))
+	if m, ok := zero.ScalarFor("decimal.Decimal", "openapi"); !ok || m.Format != "decimal" {
+		t.Errorf("ScalarFor() on a zero ScalarMap = %+v, %v", m, ok)
+	}
+
+	zero.AddFromAnnotations("net.IP", ParseAnnotationsFromText("@scalar(string, format:ipv4)\n@scalar IPAddress plugin=graphql"))
+	if m, ok := zero.ScalarFor("net.IP", "openapi"); !ok || m.Type != "string" || m.Format != "ipv4" {
+		t.Errorf("ScalarFor(unquoted) = %+v, %v", m, ok)
+	}
+	if m, ok := zero.ScalarFor("net.IP", "graphql"); !ok || m.Type != "IPAddress" {
+		t.Errorf("ScalarFor(unquoted, space separated) = %+v, %v", m, ok)
+	}
+}
+
+func TestSerializationHints(t *testing.T) {
+	if info, ok := LookupSerializationHint("unixMillis"); !ok || info.Type != "integer" || info.Format != "int64" {
+		t.Errorf("LookupSerializationHint(unixMillis) = %+v, %v", info, ok)
+	}
+	if info, ok := LookupSerializationHint(" RFC3339 "); !ok || info.Name != SerializeRFC3339 {
+		t.Errorf("LookupSerializationHint(RFC3339) = %+v, %v", info, ok)
+	}
+
+	original, _ := LookupSerializationHint("base64")
+	defer RegisterSerializationHint(original)
+	RegisterSerializationHint(SerializationHintInfo{Name: SerializeBase64, Type: "string", Format: "base64url"})
+	if info, ok := GetSerializationHint(ParseAnnotationsFromText(`@Serialize(as:"base64")`)); !ok || info.Format != "base64url" {
+		t.Errorf("GetSerializationHint() after override = %+v, %v", info, ok)
+	}
+
+	if info, ok := GetSerializationHint(ParseAnnotationsFromText("@serialize(unixMillis)")); !ok || info.Name != SerializeUnixMillis {
+		t.Errorf("GetSerializationHint(unquoted) = %+v, %v", info, ok)
+	}
+	if info, ok := GetSerializationHint(ParseAnnotationsFromText("@serialize(epochDays)")); ok || info.Name != "epochDays" {
+		t.Errorf("GetSerializationHint(unquoted unknown) = %+v, %v", info, ok)
+	}
+	if info, ok := GetSerializationHint(ParseAnnotationsFromText(`@serialize("epochDays")`)); ok || info.Name != "epochDays" {
+		t.Errorf("GetSerializationHint(unknown) = %+v, %v", info, ok)
+	}
+	if _, ok := GetSerializationHint(ParseAnnotationsFromText("@schema")); ok {
+		t.Error("GetSerializationHint() without @serialize should not find a hint")
+	}
+}
+
+func TestGetJSONShape(t *testing.T) {
+	shape, ok, err := GetJSONShape(ParseAnnotationsFromText(`@JSONShape("String", format:"date-time")`))
+	if !ok || err != nil || shape.Type != "string" || shape.Format != "date-time" {
+		t.Errorf("GetJSONShape(type) = %+v, %v, %v", shape, ok, err)
+	}
+
+	shape, ok, err = GetJSONShape(ParseAnnotationsFromText("@jsonShape(string, format:uuid)"))
+	if !ok || err != nil || shape.Type != "string" || shape.Format != "uuid" {
+		t.Errorf("GetJSONShape(unquoted) = %+v, %v, %v", shape, ok, err)
+	}
+
+	shape, ok, err = GetJSONShape(ParseAnnotationsFromText(`@jsonShape(ref:"time.Time")`))
+	if !ok || err != nil || shape.Ref != "time.Time" || shape.Type != "" {
+		t.Errorf("GetJSONShape(ref) = %+v, %v, %v", shape, ok, err)
+	}
+
+	if _, ok, err := GetJSONShape(ParseAnnotationsFromText("@jsonShape")); !ok || err == nil {
+		t.Errorf("GetJSONShape() without type or ref = %v, %v", ok, err)
+	}
+	if _, _, err := GetJSONShape(ParseAnnotationsFromText(`@jsonShape("date")`)); err == nil || err.Error() != `@jsonShape: unknown type "date", valid types are: string, number, integer, boolean, object, array` {
+		t.Errorf("GetJSONShape(unknown type) error = %v", err)
+	}
+	// Annotations built by hand have no OriginalName, the error names them by Name
+	if _, _, err := GetJSONShape([]Annotation{{Name: "jsonShape"}}); err == nil || err.Error() != "@jsonShape: a type or a ref is required" {
+		t.Errorf("GetJSONShape(built by hand) error = %v", err)
+	}
+	if shape, ok, err := GetJSONShape(ParseAnnotationsFromText("@schema")); ok || shape != nil || err != nil {
+		t.Errorf("GetJSONShape() without @jsonShape = %+v, %v, %v", shape, ok, err)
+	}
+}
+
+func TestGetInterfaceFieldPolicy(t *testing.T) {
+	policy, err := GetInterfaceFieldPolicy(ParseAnnotationsFromText(`@interfacePolicy("Union")`), InterfaceFieldPolicyFreeForm)
+	if err != nil || policy != InterfaceFieldPolicyUnion {
+		t.Errorf("GetInterfaceFieldPolicy(override) = %q, %v", policy, err)
+	}
+
+	policy, err = GetInterfaceFieldPolicy(ParseAnnotationsFromText("@interfacePolicy(union)"), InterfaceFieldPolicyFreeForm)
+	if err != nil || policy != InterfaceFieldPolicyUnion {
+		t.Errorf("GetInterfaceFieldPolicy(unquoted) = %q, %v", policy, err)
+	}
+
+	policy, err = GetInterfaceFieldPolicy(ParseAnnotationsFromText("@field"), InterfaceFieldPolicyError)
+	if err != nil || policy != InterfaceFieldPolicyError {
+		t.Errorf("GetInterfaceFieldPolicy(global) = %q, %v", policy, err)
+	}
+
+	policy, err = GetInterfaceFieldPolicy(ParseAnnotationsFromText(`@interfacePolicy("bogus")`), InterfaceFieldPolicyFreeForm)
+	if err == nil || policy != InterfaceFieldPolicyFreeForm {
+		t.Fatalf("GetInterfaceFieldPolicy(bogus) = %q, %v", policy, err)
+	}
+	if err.Error() != `@interfacePolicy: unknown policy "bogus", valid policies are: freeform, union, error` {
+		t.Errorf("GetInterfaceFieldPolicy(bogus) error = %q", err.Error())
+	}
+
+	// Annotations built by hand have no OriginalName, the error names them by Name
+	_, err = GetInterfaceFieldPolicy([]Annotation{{Name: "interfacePolicy", Params: map[string]string{"": "bogus"}}}, InterfaceFieldPolicyFreeForm)
+	if err == nil || err.Error() != `@interfacePolicy: unknown policy "bogus", valid policies are: freeform, union, error` {
+		t.Errorf("GetInterfaceFieldPolicy(built by hand) error = %v", err)
+	}
+}
+
+func TestGetUnion(t *testing.T) {
+	union, ok := GetUnion("Pet", ParseAnnotationsFromText(`@union(types:"Cat,Dog", discriminator:"kind")`))
+	if !ok || union.Name != "Pet" || len(union.Types) != 2 || union.Types[1] != "Dog" || union.Discriminator != "kind" {
+		t.Errorf("GetUnion(@union) = %+v, %v", union, ok)
+	}
+
+	unquoted := map[string][]string{
+		`@union(Cat, Dog, Bird)`:                    {"Cat", "Dog", "Bird"},
+		`@union(Dog, Cat, discriminator:"kind")`:    {"Dog", "Cat"},
+		`@union Cat Dog`:                            {"Cat", "Dog"},
+		`@union(Cat, "Dog")`:                        {"Cat", "Dog"},
+		`@union(types:[Cat, Dog], discriminator:x)`: {"Cat", "Dog"},
+	}
+	for text, want := range unquoted {
+		union, ok := GetUnion("Pet", ParseAnnotationsFromText(text))
+		if !ok || !slices.Equal(union.Types, want) {
+			t.Errorf("GetUnion(%s) = %+v, want types %v", text, union, want)
+		}
+	}
+
+	// Annotations built by hand have no RawText, their flags are read in name order
+	union, ok = GetUnion("Pet", []Annotation{{Name: "union", Params: map[string]string{"Dog": "true", "Cat": "true"}}})
+	if !ok || !slices.Equal(union.Types, []string{"Cat", "Dog"}) {
+		t.Errorf("GetUnion(built by hand) = %+v, %v", union, ok)
+	}
+
+	union, ok = GetUnion("Shape", ParseAnnotationsFromText(`@oneOf`))
+	if !ok || len(union.Types) != 0 {
+		t.Errorf("GetUnion(@oneOf) = %+v, %v", union, ok)
+	}
+
+	if _, ok := GetUnion("User", ParseAnnotationsFromText(`@schema`)); ok {
+		t.Error("GetUnion() found a union that is not declared")
+	}
+}
+
+func TestAnnotationTransformers(t *testing.T) {
+	RegisterAnnotationTransformer(func(anns []Annotation, meta EntityMeta) []Annotation {
+		if meta.Placement != FunctionAnnotationPlacement {
+			return anns
+		}
+		for _, ann := range anns {
+			if ann.Name == "secure" {
+				return anns
+			}
+		}
+		return append(anns, Annotation{Name: "secure", Params: map[string]string{}})
+	})
+	defer ResetAnnotationTransformers()
+
+	anns := ParseAnnotationsForEntity(`@route(path:"/users")`, EntityMeta{Name: "GetUsers", Placement: FunctionAnnotationPlacement})
+	if len(anns) != 2 || anns[1].Name != "secure" {
+		t.Errorf("ParseAnnotationsForEntity(function) = %+v", anns)
+	}
+
+	anns = ParseAnnotationsForEntity(`@schema`, EntityMeta{Name: "User", Placement: StructAnnotationPlacement})
+	if len(anns) != 1 {
+		t.Errorf("ParseAnnotationsForEntity(struct) = %+v", anns)
+	}
+}
+
+func TestGetEnumValueOptions(t *testing.T) {
+	opts := GetEnumValueOptions(ParseAnnotationsFromText(`@enumValue(name:"ACTIVE", deprecated)`))
+	if opts.Name != "ACTIVE" || opts.Deprecated == nil || opts.Ignore {
+		t.Errorf("GetEnumValueOptions(@enumValue) = %+v", opts)
+	}
+
+	opts = GetEnumValueOptions(ParseAnnotationsFromText("@enumValue(ACTIVE, deprecated)"))
+	if opts.Name != "ACTIVE" || opts.Deprecated == nil {
+		t.Errorf("GetEnumValueOptions(unquoted) = %+v", opts)
+	}
+	if opts := GetEnumValueOptions(ParseAnnotationsFromText("@enumValue ACTIVE")); opts.Name != "ACTIVE" || opts.Deprecated != nil {
+		t.Errorf("GetEnumValueOptions(space separated) = %+v", opts)
+	}
+
+	opts = GetEnumValueOptions(ParseAnnotationsFromText("@EnumIgnore\n@deprecated(\"use Active\")"))
+	if !opts.Ignore || opts.Name != "" || opts.Deprecated == nil || opts.Deprecated.Reason != "use Active" {
+		t.Errorf("GetEnumValueOptions(@enumIgnore) = %+v", opts)
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	text := "User represents a registered user. It is created on sign up.\n@schema(title:\"User\")\n\nUsage:\n\n\tu := NewUser()\n@deprecated\n"
+
+	desc := ParseDescription(text, false)
+	if desc.Summary != "User represents a registered user." {
+		t.Errorf("ParseDescription() summary = %q", desc.Summary)
+	}
+	want := "User represents a registered user. It is created on sign up.\n\nUsage:\n\n\tu := NewUser()"
+	if desc.Text != want {
+		t.Errorf("ParseDescription() text = %q, want %q", desc.Text, want)
+	}
+
+	md := ParseDescription(text, true)
+	if md.Text != "User represents a registered user. It is created on sign up.\n\nUsage:\n\n\tu := NewUser()" {
+		t.Errorf("ParseDescription() markdown = %q", md.Text)
+	}
+
+	if desc := ParseDescription("@schema\n@deprecated", false); desc != (Description{}) {
+		t.Errorf("ParseDescription() with only annotations = %+v", desc)
+	}
+
+	summaries := map[string]string{
+		"Uses e.g. caching. Second.":        "Uses e.g. caching.",
+		"Written by J. Smith. Second.":      "Written by J. Smith.",
+		"Made in the U.S. for you. Second.": "Made in the U.S. for you.",
+		"Is it cached? Yes.":                "Is it cached?",
+		"Version 1.2 is out. Second.":       "Version 1.2 is out.",
+		"Uses HTTP. Second.":                "Uses HTTP.",
+	}
+	for text, want := range summaries {
+		if got := ParseDescription(text, false).Summary; got != want {
+			t.Errorf("ParseDescription(%q) summary = %q, want %q", text, got, want)
+		}
+	}
+
+	marked := "User model, see [Account].\ngonnotation: @schema(ref:[Other])\n  gonnotation:@deprecated"
+	opts := ParseOptions{Marker: "gonnotation:"}
+	if desc := ParseDescriptionWithOptions(marked, false, opts); desc.Text != "User model, see [Account]." {
+		t.Errorf("ParseDescriptionWithOptions(marker) = %+v", desc)
+	}
+	if refs := ParseDescriptionRefsWithOptions(marked, opts); len(refs) != 1 || refs[0].Name != "Account" {
+		t.Errorf("ParseDescriptionRefsWithOptions(marker) = %+v", refs)
+	}
+}
+
+func TestParseDescriptionRefs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []DescriptionRef
+	}{
+		{"See [User].", []DescriptionRef{{Text: "User", Name: "User"}}},
+		{"Built by [users.New].", []DescriptionRef{{Text: "users.New", Package: "users", Name: "New"}}},
+		{"Calls [User.Save].", []DescriptionRef{{Text: "User.Save", Recv: "User", Name: "Save"}}},
+		{"Uses [github.com/acme/users.New].", []DescriptionRef{{Text: "github.com/acme/users.New", Package: "github.com/acme/users", Name: "New"}}},
+		{"@field(enum:[Active])\n@schema(ref:[User])", nil},
+		{"A [User] list.\n@field(enum:[Active])", []DescriptionRef{{Text: "User", Name: "User"}}},
+		{"No links here.", nil},
+	}
+
+	for _, tt := range tests {
+		got := ParseDescriptionRefs(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseDescriptionRefs(%q) = %+v, want %+v", tt.text, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseDescriptionRefs(%q)[%d] = %+v, want %+v", tt.text, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestGetInclusion(t *testing.T) {
+	tests := []struct {
+		text string
+		want Inclusion
+	}{
+		{"@schema", InclusionDefault},
+		{"@include", InclusionInclude},
+		{"@exclude", InclusionExclude},
+		{"@Ignore", InclusionExclude},
+		{"@include\n@ignore", InclusionExclude},
+	}
+
+	for _, tt := range tests {
+		if got := GetInclusion(ParseAnnotationsFromText(tt.text)); got != tt.want {
+			t.Errorf("GetInclusion(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestVisibilityGroups(t *testing.T) {
+	anns := ParseAnnotationsFromText("@schema(groups:\"public,admin\")\n@internal(groups:admin)\n@description(\"A user\")")
+
+	if got := FilterAnnotationsByGroup(anns, "public"); len(got) != 2 || got[1].Name != "description" {
+		t.Errorf("FilterAnnotationsByGroup(public) = %+v", got)
+	}
+	if got := FilterAnnotationsByGroup(anns, ""); len(got) != 3 {
+		t.Errorf("FilterAnnotationsByGroup(\"\") = %+v", got)
+	}
+
+	if !IsVisibleInGroup(anns, "Admin") || IsVisibleInGroup(anns, "partner") {
+		t.Error("IsVisibleInGroup() with declared groups failed")
+	}
+	if !IsVisibleInGroup(ParseAnnotationsFromText("@schema"), "partner") {
+		t.Error("IsVisibleInGroup() without groups should be visible")
+	}
+}
+
+func TestGetSecurityRequirements(t *testing.T) {
+	anns := ParseAnnotationsFromText("@route(path:\"/users\")\n@auth(scheme:\"bearer\", scopes:\"read:users,write:users\")\n@security(\"apiKey\")")
+
+	reqs := GetSecurityRequirements(anns)
+	if len(reqs) != 2 {
+		t.Fatalf("GetSecurityRequirements() returned %d requirements, want 2", len(reqs))
+	}
+	if reqs[0].Scheme != "bearer" || len(reqs[0].Scopes) != 2 || reqs[0].Scopes[0] != "read:users" {
+		t.Errorf("GetSecurityRequirements()[0] = %+v", reqs[0])
+	}
+	if reqs[1].Scheme != "apiKey" || len(reqs[1].Scopes) != 0 {
+		t.Errorf("GetSecurityRequirements()[1] = %+v", reqs[1])
+	}
+
+	tests := []struct {
+		text   string
+		scheme string
+		scopes int
+	}{
+		{"@auth", "", 0},
+		{"@auth(bearer)", "bearer", 0},
+		{"@Auth(oauth2, scopes:\"read:users\")", "oauth2", 1},
+		{"@auth(bearer, apiKey)", "", 0},
+	}
+	for _, tt := range tests {
+		reqs := GetSecurityRequirements(ParseAnnotationsFromText(tt.text))
+		if len(reqs) != 1 || reqs[0].Scheme != tt.scheme || len(reqs[0].Scopes) != tt.scopes {
+			t.Errorf("GetSecurityRequirements(%q) = %+v, want scheme %q", tt.text, reqs, tt.scheme)
+		}
+	}
+}
+
+func TestGetRouteGroup(t *testing.T) {
+	typeGroup, ok := GetRouteGroup(ParseAnnotationsFromText(`@RouteGroup("/api/v1/", middleware:"auth,logging")`))
+	if !ok || typeGroup.Prefix != "/api/v1/" || len(typeGroup.Middleware) != 2 || typeGroup.Middleware[0] != "auth" {
+		t.Fatalf("GetRouteGroup() = %+v, %v", typeGroup, ok)
+	}
+	funcGroup, ok := GetRouteGroup(ParseAnnotationsFromText(`@routeGroup(prefix:"users", middlewares:"cache")`))
+	if !ok {
+		t.Fatal("GetRouteGroup() did not find the annotation")
+	}
+
+	nested := typeGroup.Nest(*funcGroup)
+	if nested.Prefix != "/api/v1/users" || len(nested.Middleware) != 3 || nested.Middleware[2] != "cache" {
+		t.Errorf("Nest() = %+v", nested)
+	}
+	if len(typeGroup.Middleware) != 2 {
+		t.Errorf("Nest() modified the parent middleware: %v", typeGroup.Middleware)
+	}
+
+	if _, ok := GetRouteGroup(ParseAnnotationsFromText("@route")); ok {
+		t.Error("GetRouteGroup() found an annotation that is not present")
+	}
+
+	paths := map[string][]string{
+		"/api/v1/users": {"/api/v1/", "/users"},
+		"/users/{id}":   {"", "users", "{id}/"},
+		"/":             {"", "/"},
+	}
+	for want, segments := range paths {
+		if got := JoinRoutePath(segments...); got != want {
+			t.Errorf("JoinRoutePath(%q) = %q, want %q", segments, got, want)
+		}
+	}
+}
+
+func TestUnacceptedAnnotations(t *testing.T) {
+	openapi := AnnotationSpecs{Annotations: []AnnotationSpec{{Name: "schema", Aliases: []string{"model"}}}}
+	gql := AnnotationSpecs{Annotations: []AnnotationSpec{{Name: "gqlType", GlobalAliases: []string{"description"}}}}
+
+	anns := ParseAnnotationsFromText("@Model\n@gqltype\n@description(\"x\")\n@shcema\n@gqlInput")
+	got := UnacceptedAnnotations(anns, openapi, gql)
+	if len(got) != 2 || got[0].Name != "shcema" || got[1].Name != "gqlInput" {
+		t.Errorf("UnacceptedAnnotations() = %+v", got)
+	}
+}
+
+func TestParseAnnotationsWithMarker(t *testing.T) {
+	text := "User model\n@schema(title:\"ignored\")\ngonnotation: @schema(title:\"User\")\ngonnotation:@deprecated\n  gonnotation: not an annotation"
+
+	anns, err := ParseAnnotationsWithOptions(text, ParseOptions{Marker: "gonnotation:"})
+	if err != nil {
+		t.Fatalf("ParseAnnotationsWithOptions(marker) error = %v", err)
+	}
+	if len(anns) != 2 || anns[0].Params["title"] != "User" || anns[1].Name != "deprecated" {
+		t.Errorf("ParseAnnotationsWithOptions(marker) = %+v", anns)
+	}
+
+	if anns := ParseAnnotationsFromText(text); len(anns) != 1 || anns[0].Params["title"] != "ignored" {
+		t.Errorf("ParseAnnotationsFromText() = %+v", anns)
+	}
+}
+
+func TestCaseSensitiveSpecs(t *testing.T) {
+	specs := AnnotationSpecs{
+		Annotations: []AnnotationSpec{{Name: "Schema"}, {Name: "schema", Aliases: []string{"model"}}},
+	}
+	anns := ParseAnnotationsFromText("@Schema\n@SCHEMA\n@model")
+	if anns[0].OriginalName != "Schema" || anns[1].OriginalName != "SCHEMA" {
+		t.Errorf("OriginalName not preserved: %+v", anns)
+	}
+
+	if spec := specs.GetAnnotationSpecByName("schema"); spec == nil || spec.Name != "Schema" {
+		t.Errorf("case-insensitive GetAnnotationSpecByName(schema) = %v", spec)
+	}
+
+	specs.CaseSensitive = true
+	if spec := specs.GetAnnotationSpecByName("schema"); spec == nil || spec.Name != "schema" {
+		t.Errorf("case-sensitive GetAnnotationSpecByName(schema) = %v", spec)
+	}
+	if spec := specs.Index().Get("Schema"); spec == nil || spec.Name != "Schema" {
+		t.Errorf("case-sensitive Index().Get(Schema) = %v", spec)
+	}
+	if got := UnacceptedAnnotations(anns, specs); len(got) != 1 || got[0].Name != "SCHEMA" {
+		t.Errorf("case-sensitive UnacceptedAnnotations() = %+v", got)
+	}
+}
+
+func TestAnnotationLexer(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantName   string
+		wantParams map[string]string
+	}{
+		{
+			name:       "no params",
+			input:      `@schema`,
+			wantName:   "schema",
+			wantParams: map[string]string{},
+		},
+		{
+			name:       "empty parentheses",
+			input:      `@schema()`,
+			wantName:   "schema",
+			wantParams: map[string]string{},
+		},
+		{
+			name:       "space before parentheses",
+			input:      `@schema (title:"Product")`,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": "Product"},
+		},
+		{
+			name:       "comma inside quotes",
+			input:      `@schema(description:"a, b and c", title:'x, y')`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": "a, b and c", "title": "x, y"},
+		},
+		{
+			name:       "colons in urls",
+			input:      `@route(path:"/users/{id}", docs:"https://example.com/docs?a=b")`,
+			wantName:   "route",
+			wantParams: map[string]string{"path": "/users/{id}", "docs": "https://example.com/docs?a=b"},
+		},
+		{
+			name:       "unquoted url value",
+			input:      `@link(href=https://example.com:8080/a)`,
+			wantName:   "link",
+			wantParams: map[string]string{"href": "https://example.com:8080/a"},
+		},
+		{
+			name:       "escaped double quotes",
+			input:      `@schema(description:"say \"hi\", please")`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": `say "hi", please`},
+		},
+		{
+			name:       "escaped single quotes",
+			input:      `@schema(description:'it\'s fine')`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": "it's fine"},
+		},
+		{
+			name:       "escape sequences",
+			input:      `@schema(description:"line1\nline2\ttab\\slash")`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": "line1\nline2\ttab\\slash"},
+		},
+		{
+			name:       "unknown escapes are kept",
+			input:      `@field(pattern:"^\d+\.\d+$")`,
+			wantName:   "field",
+			wantParams: map[string]string{"pattern": `^\d+\.\d+$`},
+		},
+		{
+			name:       "raw string",
+			input:      "@field(pattern:`^\\w+\"x\"$`, title:\"T\")",
+			wantName:   "field",
+			wantParams: map[string]string{"pattern": `^\w+"x"$`, "title": "T"},
+		},
+		{
+			name:       "apostrophe in unquoted value",
+			input:      `@schema(description:it's fine, readonly)`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": "it's fine", "readonly": "true"},
+		},
+		{
+			name:       "other quote char inside quotes",
+			input:      `@schema(description:"it's \"quoted\"", title:'say "hi"')`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": `it's "quoted"`, "title": `say "hi"`},
+		},
+		{
+			name:       "brackets keep commas",
+			input:      `@field(enum:[a, b, c], tags:["x", "y"])`,
+			wantName:   "field",
+			wantParams: map[string]string{"enum": "[a, b, c]", "tags": `["x", "y"]`},
+		},
+		{
+			name:       "braces keep commas and colons",
+			input:      `@field(default:{"a": 1, "b": [1, 2]}, nullable)`,
+			wantName:   "field",
+			wantParams: map[string]string{"default": `{"a": 1, "b": [1, 2]}`, "nullable": "true"},
+		},
+		{
+			name:       "closing parenthesis inside quotes",
+			input:      `@schema(description:"a (b) c)", title:"T")`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": "a (b) c)", "title": "T"},
+		},
+		{
+			name:       "text after closing parenthesis is ignored",
+			input:      `@schema(title:"T") trailing`,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": "T"},
+		},
+		{
+			name:       "unclosed parenthesis",
+			input:      `@schema(title:"T", readonly`,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": "T", "readonly": "true"},
+		},
+		{
+			name:       "trailing and empty commas",
+			input:      `@schema(title:"T",, readonly,)`,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": "T", "readonly": "true"},
+		},
+		{
+			name:       "quoted positional with separator inside",
+			input:      `@schema("a:b", "c=d")`,
+			wantName:   "schema",
+			wantParams: map[string]string{"": "a:b,c=d"},
+		},
+		{
+			name:       "unquoted positional that isn't a flag",
+			input:      `@schema(/users/{id})`,
+			wantName:   "schema",
+			wantParams: map[string]string{"": "/users/{id}"},
+		},
+		{
+			name:       "empty quoted value",
+			input:      `@schema(title:"", description:'')`,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": "", "description": ""},
+		},
+		{
+			name:       "space format with parentheses in value",
+			input:      `@schema description="a (short) text" readonly`,
+			wantName:   "schema",
+			wantParams: map[string]string{"description": "a (short) text", "readonly": "true"},
+		},
+		{
+			name:       "space format with escapes",
+			input:      `@schema title="say \"hi\"" other='it\'s'`,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": `say "hi"`, "other": "it's"},
+		},
+		{
+			name:       "space format with colon separator and url",
+			input:      `@route path:"/users/{id}" docs:https://example.com`,
+			wantName:   "route",
+			wantParams: map[string]string{"path": "/users/{id}", "docs": "https://example.com"},
+		},
+		{
+			name:       "unquoted url positional",
+			input:      `@docs(https://example.com/docs, title:"Docs")`,
+			wantName:   "docs",
+			wantParams: map[string]string{"": "https://example.com/docs", "title": "Docs"},
+		},
+		{
+			name:       "space format unquoted url positional",
+			input:      `@docs https://example.com:8080/docs`,
+			wantName:   "docs",
+			wantParams: map[string]string{"": "https://example.com:8080/docs"},
+		},
+		{
+			name:       "space format quoted positional",
+			input:      `@example "John Doe"`,
+			wantName:   "example",
+			wantParams: map[string]string{"": "John Doe"},
+		},
+		{
+			name:       "space format extra spaces",
+			input:      `@schema   title="T"    readonly  `,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": "T", "readonly": "true"},
+		},
+		{
+			name:       "separator with spaces around",
+			input:      `@schema(title : "T", max = 10)`,
+			wantName:   "schema",
+			wantParams: map[string]string{"title": "T", "max": "10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ann := parseAnnotation(tt.input)
+			if ann.Name != tt.wantName {
+				t.Errorf("parseAnnotation() name = %q, want %q", ann.Name, tt.wantName)
+			}
+			if len(ann.Params) != len(tt.wantParams) {
+				t.Errorf("parseAnnotation() params = %q, want %q", ann.Params, tt.wantParams)
+			}
+			for k, expectedV := range tt.wantParams {
+				if actualV, ok := ann.Params[k]; !ok || actualV != expectedV {
+					t.Errorf("parseAnnotation() params[%q] = %q, want %q", k, actualV, expectedV)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicatePolicies(t *testing.T) {
+	text := "@schema(title:\"A\")\n@example(1)\n@Schema(title:\"B\", readonly)\n@example(2)\n@schema(title:\"A\")"
+	specs := AnnotationSpecs{Annotations: []AnnotationSpec{ExampleAnnotationSpec}}
+
+	anns, err := ParseAnnotationsWithOptions(text, ParseOptions{})
+	if err != nil || len(anns) != 5 {
+		t.Errorf("keep all = %d annotations, %v", len(anns), err)
+	}
+
+	anns, err = ParseAnnotationsWithOptions(text, ParseOptions{DuplicatePolicy: DuplicateFirstWins, Specs: specs})
+	if err != nil || len(anns) != 3 || anns[0].Params["title"] != "A" || anns[1].Name != "example" || anns[2].Name != "example" {
+		t.Errorf("first wins = %+v, %v", anns, err)
+	}
+
+	anns, _ = ParseAnnotationsWithOptions(text, ParseOptions{DuplicatePolicy: DuplicateLastWins, Specs: specs})
+	if len(anns) != 3 || anns[0].Params["title"] != "A" || anns[0].Params["readonly"] != "" {
+		t.Errorf("last wins = %+v", anns)
+	}
+
+	anns, _ = ParseAnnotationsWithOptions(text, ParseOptions{DuplicatePolicy: DuplicateMerge, Specs: specs})
+	if len(anns) != 3 || anns[0].Params["title"] != "A" || anns[0].Params["readonly"] != "true" {
+		t.Errorf("merge = %+v", anns)
+	}
+
+	anns, err = ParseAnnotationsWithOptions(text, ParseOptions{DuplicatePolicy: DuplicateError, Specs: specs})
+	errs, ok := err.(ParseErrors)
+	if !ok || len(errs) != 1 || errs[0].Line != 3 || errs[0].Annotation != "Schema" {
+		t.Fatalf("error policy error = %v", err)
+	}
+	if len(anns) != 3 || anns[0].Params["title"] != "A" {
+		t.Errorf("error policy annotations = %+v", anns)
+	}
+	if errs.Error() != "line 3: @Schema: conflicts with the previous @schema" {
+		t.Errorf("error policy message = %q", errs.Error())
+	}
+
+	anns, err = ParseAnnotationsWithOptions(text, ParseOptions{DuplicatePolicy: "frist"})
+	if len(anns) != 5 || err == nil || err.Error() != `unknown duplicate policy "frist", valid policies are: "", "error", "first", "last", "merge"` {
+		t.Errorf("unknown policy = %d annotations, %v", len(anns), err)
+	}
+
+	// Annotations built by hand have no OriginalName, the errors name them by Name
+	handBuilt := []Annotation{{Name: "schema", Params: map[string]string{"title": "A"}}, {Name: "schema", Params: map[string]string{"title": "B"}}}
+	if _, errs := resolveDuplicates(handBuilt, []int{1, 2}, ParseOptions{DuplicatePolicy: DuplicateError}); errs.Error() != "line 2: @schema: conflicts with the previous @schema" {
+		t.Errorf("error policy message (built by hand) = %q", errs.Error())
+	}
+
+	// Core annotations allowing Multiple are kept without declaring their specs
+	anns, err = ParseAnnotationsWithOptions("@example(1)\n@auth(\"bearer\")\n@example(2)\n@auth(\"apiKey\")\n@deprecated(\"a\")\n@deprecated(\"b\")", ParseOptions{DuplicatePolicy: DuplicateError})
+	if err == nil || len(anns) != 5 || len(GetExamples(anns)) != 2 || len(GetSecurityRequirements(anns)) != 2 {
+		t.Errorf("core specs = %+v, %v", anns, err)
+	}
+}
+
+func TestAnnotationAliases(t *testing.T) {
+	opts := ParseOptions{
+		Aliases:         map[string]string{"model": "schema", "@Hidden": "@exclude"},
+		DuplicatePolicy: DuplicateMerge,
+	}
+
+	anns, err := ParseAnnotationsWithOptions("@Model(title:\"User\")\n@schema(readonly)\n@hidden", opts)
+	if err != nil {
+		t.Fatalf("ParseAnnotationsWithOptions(aliases) error = %v", err)
+	}
+	if len(anns) != 2 {
+		t.Fatalf("ParseAnnotationsWithOptions(aliases) = %+v, want 2 annotations", anns)
+	}
+	if anns[0].Name != "schema" || anns[0].OriginalName != "Model" || anns[0].Params["title"] != "User" || anns[0].Params["readonly"] != "true" {
+		t.Errorf("aliased annotation = %+v", anns[0])
+	}
+	if anns[1].Name != "exclude" || GetInclusion(anns) != InclusionExclude {
+		t.Errorf("aliased core annotation = %+v", anns[1])
+	}
+}
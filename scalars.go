@@ -0,0 +1,91 @@
+package gonnotation
+
+import "strings"
+
+// ScalarMapping maps a Go type to a scalar of an output format
+type ScalarMapping struct {
+	Type   string `yaml:"type" json:"type"`                         // Scalar name in the output, e.g. "string", "UUID"
+	Format string `yaml:"format,omitempty" json:"format,omitempty"` // Scalar format, e.g. "uuid", "date-time", "decimal"
+}
+
+// ScalarMap maps Go type names (e.g. "time.Time", "github.com/google/uuid.UUID") to scalars per plugin,
+// the "" plugin key applies to every plugin without a specific mapping
+type ScalarMap map[string]map[string]ScalarMapping
+
+// ScalarAnnotationSpec is the spec of the @scalar(type, format, plugin) annotation,
+// it declares that the annotated type is mapped to a scalar instead of an object
+var ScalarAnnotationSpec = AnnotationSpec{
+	Name: "scalar",
+	Params: []AnnotationParam{
+		{
+			Name:        "type",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			Description: "Scalar name in the output, e.g. string, UUID",
+			IsRequired:  true,
+		},
+		{
+			Name:        "format",
+			Types:       []string{"string"},
+			Description: "Scalar format, e.g. uuid, date-time",
+		},
+		{
+			Name:        "plugin",
+			Types:       []string{"string"},
+			Description: "Plugin the mapping applies to, empty for all the plugins",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+	},
+	Description: "Maps the annotated type to a scalar",
+	Multiple:    true,
+}
+
+// Set adds (or replaces) the scalar mapping of a type for a plugin, use an empty plugin for all of them.
+// The map is initialized if it is nil, so the zero value is ready to use.
+func (m *ScalarMap) Set(typeName string, plugin string, mapping ScalarMapping) {
+	if *m == nil {
+		*m = make(ScalarMap)
+	}
+	if (*m)[typeName] == nil {
+		(*m)[typeName] = make(map[string]ScalarMapping)
+	}
+	(*m)[typeName][scalarPluginKey(plugin)] = mapping
+}
+
+// ScalarFor returns the scalar a type is mapped to for a plugin, falling back to the mapping for all plugins
+func (m ScalarMap) ScalarFor(typeName string, plugin string) (ScalarMapping, bool) {
+	plugins, ok := m[typeName]
+	if !ok {
+		return ScalarMapping{}, false
+	}
+	if mapping, ok := plugins[scalarPluginKey(plugin)]; ok {
+		return mapping, true
+	}
+	mapping, ok := plugins[""]
+	return mapping, ok
+}
+
+// AddFromAnnotations adds the mappings declared by the @scalar annotations of a type,
+// annotations take precedence over the mappings already in the map (usually loaded from config)
+func (m *ScalarMap) AddFromAnnotations(typeName string, annotations []Annotation) {
+	for _, ann := range annotations {
+		if !ScalarAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		scalarType, ok := ScalarAnnotationSpec.GetParamValue("type", ann)
+		if !ok || scalarType == "" {
+			continue
+		}
+		format, _ := ScalarAnnotationSpec.GetParamValue("format", ann)
+		plugin, _ := ScalarAnnotationSpec.GetParamValue("plugin", ann)
+		m.Set(typeName, plugin, ScalarMapping{Type: scalarType, Format: format})
+	}
+}
+
+// scalarPluginKey normalizes a plugin name for the lookups, plugin names are matched case-insensitively
+func scalarPluginKey(plugin string) string {
+	return strings.ToLower(strings.TrimSpace(plugin))
+}
@@ -0,0 +1,162 @@
+package gonnotation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SerializationHint is the name of a wire representation for a field, e.g. "unixMillis"
+type SerializationHint string
+
+const (
+	SerializeUnixSeconds SerializationHint = "unix"
+	SerializeUnixMillis  SerializationHint = "unixMillis"
+	SerializeRFC3339     SerializationHint = "rfc3339"
+	SerializeBase64      SerializationHint = "base64"
+	SerializeString      SerializationHint = "string"
+)
+
+// SerializationHintInfo describes how a serialization hint is represented on the wire,
+// generators map the Type and Format to their own format-specific representation
+type SerializationHintInfo struct {
+	Name        SerializationHint `yaml:"name" json:"name"`
+	Type        string            `yaml:"type" json:"type"`     // Wire type: "integer", "number", "string", "boolean"
+	Format      string            `yaml:"format" json:"format"` // Wire format, e.g. "int64", "date-time", "byte"
+	Description string            `yaml:"description" json:"description"`
+}
+
+var (
+	serializationHintsMu sync.RWMutex
+	serializationHints   = map[SerializationHint]SerializationHintInfo{
+		SerializeUnixSeconds: {Name: SerializeUnixSeconds, Type: "integer", Format: "int64", Description: "Seconds since the Unix epoch"},
+		SerializeUnixMillis:  {Name: SerializeUnixMillis, Type: "integer", Format: "int64", Description: "Milliseconds since the Unix epoch"},
+		SerializeRFC3339:     {Name: SerializeRFC3339, Type: "string", Format: "date-time", Description: "RFC 3339 date-time string"},
+		SerializeBase64:      {Name: SerializeBase64, Type: "string", Format: "byte", Description: "Base64 encoded bytes"},
+		SerializeString:      {Name: SerializeString, Type: "string", Description: "Value encoded as a string"},
+	}
+)
+
+// SerializeAnnotationSpec is the spec of the @serialize(as="unixMillis") annotation
+var SerializeAnnotationSpec = AnnotationSpec{
+	Name: "serialize",
+	Params: []AnnotationParam{
+		{
+			Name:        "as",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			Description: "Name of the serialization hint, built-ins are: unix, unixMillis, rfc3339, base64, string",
+			IsRequired:  true,
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		FieldAnnotationPlacement,
+	},
+	Description: "Indicates how the field is represented on the wire",
+}
+
+// RegisterSerializationHint registers (or replaces) a serialization hint in the shared registry
+func RegisterSerializationHint(info SerializationHintInfo) {
+	serializationHintsMu.Lock()
+	defer serializationHintsMu.Unlock()
+	serializationHints[info.Name] = info
+}
+
+// LookupSerializationHint finds a registered serialization hint by name (case-insensitive)
+func LookupSerializationHint(name string) (SerializationHintInfo, bool) {
+	serializationHintsMu.RLock()
+	defer serializationHintsMu.RUnlock()
+	if info, ok := serializationHints[SerializationHint(name)]; ok {
+		return info, true
+	}
+	for k, info := range serializationHints {
+		if strings.EqualFold(string(k), strings.TrimSpace(name)) {
+			return info, true
+		}
+	}
+	return SerializationHintInfo{}, false
+}
+
+// GetSerializationHint looks for a @serialize annotation in the list and resolves its hint from the registry.
+// Returns the hint name as given and false if it isn't registered, so callers can report it.
+func GetSerializationHint(annotations []Annotation) (SerializationHintInfo, bool) {
+	for _, ann := range annotations {
+		if !SerializeAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		as, _ := SerializeAnnotationSpec.GetParamValue("as", ann)
+		if info, ok := LookupSerializationHint(as); ok {
+			return info, true
+		}
+		return SerializationHintInfo{Name: SerializationHint(as)}, false
+	}
+	return SerializationHintInfo{}, false
+}
+
+// JSONShape describes the JSON produced by a type with a custom MarshalJSON or MarshalText method,
+// whose fields don't tell its wire representation
+type JSONShape struct {
+	Type   string `yaml:"type,omitempty" json:"type,omitempty"`     // JSON type: "string", "number", "integer", "boolean", "object" or "array"
+	Format string `yaml:"format,omitempty" json:"format,omitempty"` // Format of the value, e.g. "date-time", "uuid"
+	Ref    string `yaml:"ref,omitempty" json:"ref,omitempty"`       // Go type marshaled the same way, used instead of Type
+}
+
+// JSONShapeAnnotationSpec is the spec of the @jsonShape(type="string", format="date-time") annotation
+var JSONShapeAnnotationSpec = AnnotationSpec{
+	Name: "jsonShape",
+	Params: []AnnotationParam{
+		{
+			Name:        "type",
+			IsDefault:   true,
+			Aliases:     []string{""},
+			Types:       []string{"string"},
+			EnumValues:  []string{"string", "number", "integer", "boolean", "object", "array"},
+			Description: "JSON type the value is marshaled to",
+		},
+		{
+			Name:        "format",
+			Types:       []string{"string"},
+			Description: "Format of the value, e.g. date-time, uuid",
+		},
+		{
+			Name:        "ref",
+			Types:       []string{"string"},
+			Description: "Go type marshaled the same way, used instead of type",
+		},
+	},
+	ValidOn: []AnnotationPlacement{
+		StructAnnotationPlacement,
+		FieldAnnotationPlacement,
+	},
+	Description: "Overrides the schema inferred from the fields of a type with a custom JSON marshaler",
+}
+
+// GetJSONShape looks for a @jsonShape annotation in the list. Returns the shape and true if found,
+// a *ParseError is returned along with the shape if neither a known type nor a ref is given.
+func GetJSONShape(annotations []Annotation) (*JSONShape, bool, error) {
+	for _, ann := range annotations {
+		if !JSONShapeAnnotationSpec.Matches(ann.Name) {
+			continue
+		}
+		shape := &JSONShape{}
+		shape.Type, _ = JSONShapeAnnotationSpec.GetParamValue("type", ann)
+		shape.Format, _ = JSONShapeAnnotationSpec.GetParamValue("format", ann)
+		shape.Ref, _ = JSONShapeAnnotationSpec.GetParamValue("ref", ann)
+		shape.Type = strings.ToLower(strings.TrimSpace(shape.Type))
+
+		validTypes := JSONShapeAnnotationSpec.GetParam("type").EnumValues
+		switch {
+		case shape.Type == "" && shape.Ref == "":
+			return shape, true, &ParseError{Annotation: ann.WrittenName(), Msg: "a type or a ref is required"}
+		case shape.Type != "" && !slices.Contains(validTypes, shape.Type):
+			return shape, true, &ParseError{
+				Annotation: ann.WrittenName(),
+				Msg:        fmt.Sprintf("unknown type %q, valid types are: %s", shape.Type, strings.Join(validTypes, ", ")),
+			}
+		}
+		return shape, true, nil
+	}
+	return nil, false, nil
+}
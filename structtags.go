@@ -0,0 +1,142 @@
+package gonnotation
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TagAnnotationMapping defines how an annotation is synthesized from a struct tag
+// For example, validate:"required,max=10" can be mapped to @validate(required, max=10)
+// and gorm:"primaryKey" to @id. Map validator tags to @validate so ParseConstraints
+// reads their size rules following the kind of the field.
+type TagAnnotationMapping struct {
+	Tag        string `yaml:"tag" json:"tag"`               // Struct tag key, e.g. "validate", "gorm"
+	Option     string `yaml:"option" json:"option"`         // Tag option that must be present, e.g. "primaryKey"; empty maps all the tag options as params
+	Annotation string `yaml:"annotation" json:"annotation"` // Name of the synthesized annotation, e.g. "field", "id"
+}
+
+// ParseStructTag parses the value of the given key from a raw struct tag into StructTags.
+// Options can be separated by commas or semicolons and use the same key:value / key=value
+// syntax as annotation params, e.g. `myanntag:"ignore,name:'test'"` results in
+// {"ignore": "true", "name": "test"}. Returns false if the key is not present in the tag.
+func ParseStructTag(tag string, key string) (StructTags, bool) {
+	value, ok := reflect.StructTag(strings.Trim(tag, "`")).Lookup(key)
+	if !ok {
+		return nil, false
+	}
+	return parseTagOptions(value), true
+}
+
+// parseTagOptions parses the options of a struct tag value, see ParseStructTag
+func parseTagOptions(value string) StructTags {
+	sepReplacer := strings.NewReplacer(";", ",")
+	return StructTags(parseParamsParentheses(sepReplacer.Replace(value)))
+}
+
+// fieldTagOptions cuts a struct tag value at its first dive option, in validator syntax
+// the options after dive apply to the items of a collection and not to the field
+func fieldTagOptions(value string) string {
+	opts := strings.Split(value, ",")
+	for i, opt := range opts {
+		if strings.TrimSpace(opt) == "dive" {
+			return strings.Join(opts[:i], ",")
+		}
+	}
+	return value
+}
+
+// AnnotationsFromStructTag synthesizes annotations from a raw struct tag using the given mappings,
+// so codebases that already describe their fields through struct tags get annotations without comments
+func AnnotationsFromStructTag(tag string, mappings []TagAnnotationMapping) []Annotation {
+	var annotations []Annotation
+
+	for _, m := range mappings {
+		if m.Tag == "" || m.Annotation == "" {
+			continue
+		}
+		value, ok := reflect.StructTag(strings.Trim(tag, "`")).Lookup(m.Tag)
+		if !ok {
+			continue
+		}
+		// Options after dive describe the items of a collection, they would overwrite the field ones
+		tags := parseTagOptions(fieldTagOptions(value))
+		rawText := m.Tag + `:"` + value + `"`
+
+		// Map every option of the tag as a param of the annotation
+		if m.Option == "" {
+			annotations = append(annotations, Annotation{
+				Name:         m.Annotation,
+				OriginalName: m.Annotation,
+				Params:       tags,
+				RawText:      rawText,
+			})
+			continue
+		}
+
+		// Map a single option, keeping its value (if any) as the default param
+		for k, v := range tags {
+			if !strings.EqualFold(k, m.Option) {
+				continue
+			}
+			params := make(map[string]string)
+			if v != "true" {
+				params[""] = v
+			}
+			annotations = append(annotations, Annotation{
+				Name:         m.Annotation,
+				OriginalName: m.Annotation,
+				Params:       params,
+				RawText:      rawText,
+			})
+			break
+		}
+	}
+
+	return annotations
+}
+
+// DefaultFieldNameTags is the default priority of the struct tags used to compute field output names
+var DefaultFieldNameTags = []string{"json", "yaml"}
+
+// FieldTagName is the output name of a field computed from its struct tags
+type FieldTagName struct {
+	Name      string `yaml:"name" json:"name"`           // Output name of the field
+	Tag       string `yaml:"tag" json:"tag"`             // Struct tag the name was taken from, empty if no tag was found
+	OmitEmpty bool   `yaml:"omitEmpty" json:"omitEmpty"` // The tag has the omitempty (or omitzero) option
+	Skip      bool   `yaml:"skip" json:"skip"`           // The tag is "-", the field must be skipped
+}
+
+// ResolveFieldTagName computes the output name of a field from the first tag of the priority list present in the
+// raw struct tag (DefaultFieldNameTags if none is given), following encoding/json conventions: `json:"-"` skips the
+// field, `json:"-,"` names it "-" and an empty name keeps the Go name.
+func ResolveFieldTagName(goName string, tag string, tagPriority ...string) FieldTagName {
+	if len(tagPriority) == 0 {
+		tagPriority = DefaultFieldNameTags
+	}
+
+	st := reflect.StructTag(strings.Trim(tag, "`"))
+	for _, key := range tagPriority {
+		value, ok := st.Lookup(key)
+		if !ok {
+			continue
+		}
+		if value == "-" {
+			return FieldTagName{Name: goName, Tag: key, Skip: true}
+		}
+
+		parts := strings.Split(value, ",")
+		result := FieldTagName{Name: strings.TrimSpace(parts[0]), Tag: key}
+		if result.Name == "" {
+			result.Name = goName
+		}
+		for _, opt := range parts[1:] {
+			switch strings.TrimSpace(opt) {
+			case "omitempty", "omitzero":
+				result.OmitEmpty = true
+			}
+		}
+		return result
+	}
+
+	return FieldTagName{Name: goName}
+}
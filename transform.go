@@ -0,0 +1,61 @@
+package gonnotation
+
+import "sync"
+
+// EntityMeta describes the element a list of annotations belongs to
+type EntityMeta struct {
+	Name      string              // Go name of the element, e.g. "User", "User.Email", "GetUser"
+	Placement AnnotationPlacement // Where the annotations were found
+}
+
+// AnnotationTransformer post-processes the annotations of an element right after parsing,
+// e.g. to enforce org-wide policies like adding @secure to every @route lacking it
+type AnnotationTransformer func(annotations []Annotation, meta EntityMeta) []Annotation
+
+var (
+	annotationTransformersMu sync.RWMutex
+	annotationTransformers   []AnnotationTransformer
+)
+
+// RegisterAnnotationTransformer registers a transformer run by ParseAnnotationsForEntity,
+// transformers run in registration order and each one receives the output of the previous one
+func RegisterAnnotationTransformer(t AnnotationTransformer) {
+	if t == nil {
+		return
+	}
+	annotationTransformersMu.Lock()
+	defer annotationTransformersMu.Unlock()
+	annotationTransformers = append(annotationTransformers, t)
+}
+
+// ResetAnnotationTransformers removes all the registered transformers
+func ResetAnnotationTransformers() {
+	annotationTransformersMu.Lock()
+	defer annotationTransformersMu.Unlock()
+	annotationTransformers = nil
+}
+
+// ApplyAnnotationTransformers runs the registered transformers over the annotations of an element
+func ApplyAnnotationTransformers(annotations []Annotation, meta EntityMeta) []Annotation {
+	annotationTransformersMu.RLock()
+	transformers := annotationTransformers
+	annotationTransformersMu.RUnlock()
+
+	for _, t := range transformers {
+		annotations = t(annotations, meta)
+	}
+	return annotations
+}
+
+// ParseAnnotationsForEntity parses the annotations of an element and runs the registered transformers over them
+func ParseAnnotationsForEntity(text string, meta EntityMeta) []Annotation {
+	annotations, _ := ParseAnnotationsForEntityWithOptions(text, meta, ParseOptions{})
+	return annotations
+}
+
+// ParseAnnotationsForEntityWithOptions parses the annotations of an element using the given options
+// and runs the registered transformers over them
+func ParseAnnotationsForEntityWithOptions(text string, meta EntityMeta, opts ParseOptions) ([]Annotation, error) {
+	annotations, err := ParseAnnotationsWithOptions(text, opts)
+	return ApplyAnnotationTransformers(annotations, meta), err
+}
@@ -1,13 +1,26 @@
 // Package gonnotation defines types related to code annotations used for code generation and metadata.
 package gonnotation
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // Annotation represents a parsed annotation from Go comments (@name(params))
 type Annotation struct {
-	Name    string            // e.g., "gqlType", "openapi"
-	Params  map[string]string // key-value parameters
-	RawText string            // original text
+	Name         string            // e.g., "gqlType", "openapi"
+	OriginalName string            // name as written in the comment, with its original casing
+	Params       map[string]string // key-value parameters
+	RawText      string            // original text
+}
+
+// WrittenName returns the name of the annotation as written in the comment, falling back to Name
+// for the annotations without OriginalName (built by hand or by transformers)
+func (a *Annotation) WrittenName() string {
+	if a.OriginalName != "" {
+		return a.OriginalName
+	}
+	return a.Name
 }
 
 // AnnotationPlacement represents where an annotation can be used
@@ -89,9 +102,74 @@ func (a *AnnotationSpec) GetParamValue(name string, ann Annotation) (string, boo
 	if p == nil {
 		return "", false
 	}
+	if p.IsDefault {
+		return a.GetDefaultParamValue(ann)
+	}
 	return p.GetValue(ann)
 }
 
+// GetDefaultParamValue returns the value of the default (IsDefault) param of the spec. Unquoted values
+// written without a key are parsed as boolean flags, e.g. @union(Cat, Dog) has the {"Cat": "true", "Dog": "true"}
+// params, so unless the param is given by name the flags not named after a param of the spec are read as
+// positional values. A list param ([]string type) takes all the positional values in order, any other param
+// a single one since several are ambiguous.
+func (a *AnnotationSpec) GetDefaultParamValue(ann Annotation) (string, bool) {
+	var p *AnnotationParam
+	for i := range a.Params {
+		if a.Params[i].IsDefault {
+			p = &a.Params[i]
+			break
+		}
+	}
+	if p == nil {
+		return "", false
+	}
+	for k := range ann.Params {
+		if k != "" && (strings.EqualFold(k, p.Name) || slices.ContainsFunc(p.Aliases, func(alias string) bool { return strings.EqualFold(k, alias) })) {
+			return p.GetValue(ann)
+		}
+	}
+
+	var values []string
+	hasFlags := false
+	for _, pos := range positionalParams(ann) {
+		if pos.Flag {
+			if a.GetParam(pos.Value) != nil {
+				continue
+			}
+			hasFlags = true
+		}
+		values = append(values, pos.Value)
+	}
+	if !hasFlags || (len(values) > 1 && !slices.Contains(p.Types, "[]string")) {
+		return p.GetValue(ann)
+	}
+	return strings.Join(values, ","), true
+}
+
+// GetParamStringList returns the value of a param of the spec split as a list, see Annotation.GetParamStringList
+func (a *AnnotationSpec) GetParamStringList(name string, ann Annotation) ([]string, bool) {
+	v, ok := a.GetParamValue(name, ann)
+	if !ok || strings.TrimSpace(v) == "" {
+		return nil, false
+	}
+	return splitParamList(v), true
+}
+
+// Matches checks if an annotation name is the name or one of the aliases of the spec (case-insensitive)
+func (a *AnnotationSpec) Matches(name string) bool {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, a.Name) {
+		return true
+	}
+	for _, alias := range a.Aliases {
+		if strings.EqualFold(name, alias) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *AnnotationSpec) IsValidPlacement(placement AnnotationPlacement) bool {
 	if len(a.ValidOn) == 0 {
 		return true
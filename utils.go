@@ -6,12 +6,18 @@ import "strings"
 // For example, with prefix "@gql" and suffix "type", it matches "@gqltype" or "@gqlType"
 // If prefix is empty, only matches the suffix alone
 func MatchesAnnotation(annName string, prefix string, suffixes ...string) bool {
-	annName = strings.ToLower(annName)
-	prefix = strings.ToLower(strings.TrimPrefix(prefix, "@"))
+	return MatchesAnnotationWithCase(annName, prefix, false, suffixes...)
+}
+
+// MatchesAnnotationWithCase checks if an annotation name matches the prefix and suffixes as MatchesAnnotation does,
+// when caseSensitive is true the name must match exactly, e.g. prefix "gql" and suffix "Type" only match "gqlType"
+func MatchesAnnotationWithCase(annName string, prefix string, caseSensitive bool, suffixes ...string) bool {
+	annName = NormalizeAnnotationNameWithCase(annName, caseSensitive)
+	prefix = NormalizeAnnotationNameWithCase(strings.TrimPrefix(prefix, "@"), caseSensitive)
 
 	// Check each suffix
 	for _, suffix := range suffixes {
-		suffix = strings.ToLower(suffix)
+		suffix = NormalizeAnnotationNameWithCase(suffix, caseSensitive)
 
 		// Match with prefix: e.g., "gqltype" when prefix is "gql"
 		if prefix != "" {
@@ -29,3 +35,38 @@ func MatchesAnnotation(annName string, prefix string, suffixes ...string) bool {
 
 	return false
 }
+
+// AnnotationMatcher is a precompiled set of the names accepted by MatchesAnnotation for a prefix and suffixes,
+// use it when the same prefix and suffixes are checked against many annotations
+type AnnotationMatcher struct {
+	names         map[string]struct{}
+	caseSensitive bool
+}
+
+// NewAnnotationMatcher builds an AnnotationMatcher with the same semantics as MatchesAnnotation
+func NewAnnotationMatcher(prefix string, suffixes ...string) *AnnotationMatcher {
+	return NewAnnotationMatcherWithCase(prefix, false, suffixes...)
+}
+
+// NewAnnotationMatcherWithCase builds an AnnotationMatcher with the same semantics as MatchesAnnotationWithCase
+func NewAnnotationMatcherWithCase(prefix string, caseSensitive bool, suffixes ...string) *AnnotationMatcher {
+	prefix = NormalizeAnnotationNameWithCase(strings.TrimPrefix(prefix, "@"), caseSensitive)
+	m := &AnnotationMatcher{
+		names:         make(map[string]struct{}, len(suffixes)*2),
+		caseSensitive: caseSensitive,
+	}
+	for _, suffix := range suffixes {
+		suffix = NormalizeAnnotationNameWithCase(suffix, caseSensitive)
+		if prefix != "" {
+			m.names[prefix+suffix] = struct{}{}
+		}
+		m.names[suffix] = struct{}{}
+	}
+	return m
+}
+
+// Matches checks if an annotation name matches the prefix and suffixes of the matcher
+func (m *AnnotationMatcher) Matches(annName string) bool {
+	_, ok := m.names[NormalizeAnnotationNameWithCase(annName, m.caseSensitive)]
+	return ok
+}